@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rs/zerolog"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	serv "github.com/shaileshhb/quiz/src/service"
+)
+
+// kindStatus is the status code errors of services are reported with by their kind.
+var kindStatus = map[error]int{
+	serv.ErrInvalid:      http.StatusBadRequest,
+	serv.ErrUnauthorized: http.StatusUnauthorized,
+	serv.ErrForbidden:    http.StatusForbidden,
+	serv.ErrNotFound:     http.StatusNotFound,
+	serv.ErrConflict:     http.StatusConflict,
+	serv.ErrGone:         http.StatusGone,
+}
+
+// ErrorResponse is the body of every response reporting an error.
+type ErrorResponse struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails will contain a machine readable code of the error along with its message, and ID of the request
+// which can be used to find it in logs. Violations are listed only for requests which are not valid.
+type ErrorDetails struct {
+	Code       string             `json:"code"`
+	Message    string             `json:"message"`
+	RequestID  string             `json:"requestID"`
+	Violations []models.Violation `json:"violations,omitempty"`
+}
+
+// NewErrorHandler will create handler reporting errors returned by routes. Errors of services and validation errors
+// are reported with their status code, unexpected errors are logged and reported as internal errors without details.
+func NewErrorHandler(log zerolog.Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		status, details := describeError(err)
+		details.RequestID = requestID(c)
+
+		event := log.Warn()
+		if status >= http.StatusInternalServerError {
+			event = log.Error()
+		}
+		event.Err(err).Int("status", status).Str("requestID", details.RequestID).Msg("")
+
+		return c.Status(status).JSON(ErrorResponse{Error: details})
+	}
+}
+
+// describeError will return status code and details which err is reported with.
+func describeError(err error) (int, ErrorDetails) {
+	var validationErr *models.ValidationError
+	var serviceErr *serv.Error
+	var fiberErr *fiber.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusUnprocessableEntity, ErrorDetails{
+			Code:       "validation_failed",
+			Message:    validationErr.Error(),
+			Violations: validationErr.Violations,
+		}
+	case errors.As(err, &serviceErr):
+		status, ok := kindStatus[serviceErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+
+		return status, ErrorDetails{Code: serviceErr.Code, Message: serviceErr.Message}
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code, ErrorDetails{Code: statusCode(fiberErr.Code), Message: fiberErr.Message}
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound, ErrorDetails{Code: statusCode(http.StatusNotFound), Message: err.Error()}
+	default:
+		return http.StatusInternalServerError, ErrorDetails{
+			Code:    statusCode(http.StatusInternalServerError),
+			Message: "internal server error",
+		}
+	}
+}
+
+// statusCode will return code of errors which are described only by status, such as "not_found" for 404.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+}
+
+// requestID will return ID of the request, which is assigned by the request ID middleware.
+func requestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("requestid").(string); ok {
+		return id
+	}
+
+	return c.GetRespHeader(fiber.HeaderXRequestID)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	serv "github.com/shaileshhb/quiz/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"Not Found", serv.ErrQuizNotFound, http.StatusNotFound, "quiz_not_found", "quiz not found"},
+		{"Conflict", serv.ErrAttemptsExhausted, http.StatusConflict, "attempts_exhausted", "user has already attempted this quiz"},
+		{"Gone", serv.ErrTimeExceeded, http.StatusGone, "time_exceeded", "maximum time exceeded for this quiz"},
+		{"Forbidden", serv.ErrNotQuizCreator, http.StatusForbidden, "not_quiz_creator", "only creator of the quiz can modify it"},
+		{"Invalid Request", serv.InvalidRequest(errors.New("invalid UUID length: 3")), http.StatusBadRequest, "invalid_request", "invalid UUID length: 3"},
+		{"Wrapped", fmt.Errorf("loading attempt: %w", serv.ErrAttemptNotFound), http.StatusNotFound, "attempt_not_found", "attempt not found"},
+		{"Fiber Error", fiber.NewError(fiber.StatusUnauthorized, "Unauthorized"), http.StatusUnauthorized, "unauthorized", "Unauthorized"},
+		{"Record Not Found", db.ErrRecordNotFound, http.StatusNotFound, "not_found", db.ErrRecordNotFound.Error()},
+		{"Unexpected", errors.New("connection refused"), http.StatusInternalServerError, "internal_server_error", "internal server error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+			app.Use(requestid.New())
+			app.Get("/", func(c *fiber.Ctx) error {
+				return test.err
+			})
+
+			resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.status, resp.StatusCode)
+
+			response := ErrorResponse{}
+			err := json.NewDecoder(resp.Body).Decode(&response)
+			assert.Nil(t, err)
+			assert.Equal(t, test.code, response.Error.Code)
+			assert.Equal(t, test.message, response.Error.Message)
+			assert.NotEmpty(t, response.Error.RequestID)
+			assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), response.Error.RequestID)
+			assert.Empty(t, response.Error.Violations)
+		})
+	}
+
+	t.Run("Validation Error", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+		app.Get("/", func(c *fiber.Ctx) error {
+			return (&models.Quiz{}).Validate()
+		})
+
+		resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		response := ErrorResponse{}
+		err := json.NewDecoder(resp.Body).Decode(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, "validation_failed", response.Error.Code)
+		assert.Equal(t, "title must be specified", response.Error.Message)
+		assert.Len(t, response.Error.Violations, 2)
+	})
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/security"
+	serv "github.com/shaileshhb/quiz/src/service"
+)
+
+// mediaCacheControl allows browsers to cache media for a year, content of media never changes once it is uploaded.
+const mediaCacheControl = "private, max-age=31536000, immutable"
+
+// mediaController contains reference to media service and logger
+type mediaController struct {
+	service serv.MediaService
+	log     zerolog.Logger
+}
+
+// NewMediaController will create new instance of mediaController.
+func NewMediaController(service serv.MediaService, log zerolog.Logger) *mediaController {
+	return &mediaController{
+		service: service,
+		log:     log,
+	}
+}
+
+// RegisterRoute registers all endpoints to router.
+func (controller *mediaController) RegisterRoute(router fiber.Router) {
+	router.Post("/media", security.MandatoryAuthMiddleware, controller.UploadMedia)
+	router.Get("/media/:mediaID", security.MandatoryAuthMiddleware, controller.DownloadMedia)
+
+	controller.log.Info().Msg("Media routes registered")
+}
+
+// UploadMedia will store image sent as "file" field of multipart form.
+func (controller *mediaController) UploadMedia(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return serv.InvalidRequest(errors.New("file must be specified"))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+	defer file.Close()
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	media, err := controller.service.Upload(fileHeader.Filename, file, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(media)
+}
+
+// DownloadMedia will send content of uploaded media. Media is identified by its ID, which is used as ETag as well.
+func (controller *mediaController) DownloadMedia(c *fiber.Ctx) error {
+	mediaID, err := uuid.Parse(c.Params("mediaID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	media, err := controller.service.GetMedia(mediaID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	etag := strconv.Quote(media.ID.String())
+
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		c.Set(fiber.HeaderCacheControl, mediaCacheControl)
+		c.Set(fiber.HeaderETag, etag)
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	content, err := controller.service.Open(mediaID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, mediaCacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderContentType, media.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	// content is closed by fiber once it is sent.
+	return c.Status(http.StatusOK).SendStream(content, int(media.Size))
+}
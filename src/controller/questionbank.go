@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/security"
+	serv "github.com/shaileshhb/quiz/src/service"
+)
+
+// questionBankController contains reference to question bank service and logger
+type questionBankController struct {
+	service serv.QuestionBankService
+	log     zerolog.Logger
+}
+
+// NewQuestionBankController will create new instance of questionBankController.
+func NewQuestionBankController(service serv.QuestionBankService, log zerolog.Logger) *questionBankController {
+	return &questionBankController{
+		service: service,
+		log:     log,
+	}
+}
+
+// RegisterRoute registers all endpoints to router.
+func (controller *questionBankController) RegisterRoute(router fiber.Router) {
+	router.Post("/questions", security.MandatoryAuthMiddleware, controller.CreateQuestion)
+	router.Get("/questions", security.MandatoryAuthMiddleware, controller.ListQuestions)
+	router.Get("/questions/:questionID", security.MandatoryAuthMiddleware, controller.GetQuestion)
+	router.Put("/questions/:questionID", security.MandatoryAuthMiddleware, controller.UpdateQuestion)
+	router.Delete("/questions/:questionID", security.MandatoryAuthMiddleware, controller.DeleteQuestion)
+	router.Get("/questions/:questionID/statistics", security.MandatoryAuthMiddleware, controller.GetQuestionStatistics)
+
+	controller.log.Info().Msg("Question bank routes registered")
+}
+
+// CreateQuestion will add new question to question bank.
+func (controller *questionBankController) CreateQuestion(c *fiber.Ctx) error {
+	question := models.BankQuestion{}
+
+	err := c.BodyParser(&question)
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	err = question.Validate()
+	if err != nil {
+		return err
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+	question.CreatedBy = user.ID
+
+	err = controller.service.Create(&question)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(map[string]uuid.UUID{
+		"questionID": question.ID,
+	})
+}
+
+// ListQuestions will list questions of question bank of the user, optionally filtered by tag.
+func (controller *questionBankController) ListQuestions(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	questions, err := controller.service.List(user.ID, c.Query("tag"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(questions)
+}
+
+// GetQuestion will return question of question bank.
+func (controller *questionBankController) GetQuestion(c *fiber.Ctx) error {
+	questionID, err := uuid.Parse(c.Params("questionID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	question, err := controller.service.GetQuestion(questionID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(question)
+}
+
+// UpdateQuestion will replace question of question bank.
+func (controller *questionBankController) UpdateQuestion(c *fiber.Ctx) error {
+	question := models.BankQuestion{}
+
+	err := c.BodyParser(&question)
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	question.ID, err = uuid.Parse(c.Params("questionID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	err = question.Validate()
+	if err != nil {
+		return err
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	err = controller.service.Update(&question, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(question)
+}
+
+// DeleteQuestion will remove question from question bank.
+func (controller *questionBankController) DeleteQuestion(c *fiber.Ctx) error {
+	questionID, err := uuid.Parse(c.Params("questionID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	err = controller.service.Delete(questionID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// GetQuestionStatistics will return statistics of responses to question of question bank across all quizzes.
+func (controller *questionBankController) GetQuestionStatistics(c *fiber.Ctx) error {
+	questionID, err := uuid.Parse(c.Params("questionID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	statistics, err := controller.service.GetStatistics(questionID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(statistics)
+}
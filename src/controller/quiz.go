@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/quizformat"
 	"github.com/shaileshhb/quiz/src/security"
 	serv "github.com/shaileshhb/quiz/src/service"
 )
@@ -28,7 +33,15 @@ func NewQuizController(service serv.QuizService, log zerolog.Logger) *quizContro
 // RegisterRoute registers all endpoints to router.
 func (controller *quizController) RegisterRoute(router fiber.Router) {
 	router.Post("/quizzes", security.MandatoryAuthMiddleware, controller.CreateQuiz)
+	router.Post("/quizzes/import", security.MandatoryAuthMiddleware, controller.ImportQuizzes)
+	router.Get("/quizzes", security.MandatoryAuthMiddleware, controller.ListQuizzes)
 	router.Get("/quizzes/:quizID", security.MandatoryAuthMiddleware, controller.GetQuiz)
+	router.Put("/quizzes/:quizID", security.MandatoryAuthMiddleware, controller.UpdateQuiz)
+	router.Patch("/quizzes/:quizID", security.MandatoryAuthMiddleware, controller.PatchQuiz)
+	router.Delete("/quizzes/:quizID", security.MandatoryAuthMiddleware, controller.DeleteQuiz)
+	router.Get("/quizzes/:quizID/versions", security.MandatoryAuthMiddleware, controller.GetQuizVersions)
+	router.Get("/quizzes/:quizID/versions/:version", security.MandatoryAuthMiddleware, controller.GetQuizVersion)
+	router.Get("/quizzes/:quizID/diff", security.MandatoryAuthMiddleware, controller.DiffQuizVersions)
 
 	controller.log.Info().Msg("Quiz routes registered")
 }
@@ -39,26 +52,21 @@ func (controller *quizController) CreateQuiz(c *fiber.Ctx) error {
 
 	err := c.BodyParser(&quiz)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	err = quiz.Validate()
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+	quiz.CreatedBy = user.ID
+
 	err = controller.service.Create(&quiz)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusCreated).JSON(map[string]uuid.UUID{
@@ -66,23 +74,270 @@ func (controller *quizController) CreateQuiz(c *fiber.Ctx) error {
 	})
 }
 
-// CreateQuiz will create new quiz.
+// ImportQuizzes will create quizzes from CSV, GIFT or Moodle XML file sent as "file" field of multipart form.
+// Format is guessed from extension of the file unless "format" field is sent, and questions which do not belong
+// to a quiz named in the file are added to a quiz titled by "title" field or name of the file.
+func (controller *quizController) ImportQuizzes(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return serv.InvalidRequest(errors.New("file must be specified"))
+	}
+
+	format := c.FormValue("format", quizformat.FormatFromFileName(fileHeader.Filename))
+	title := c.FormValue("title", strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename)))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+	defer file.Close()
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	report, err := controller.service.Import(format, file, title, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Errors) > 0 {
+		return c.Status(http.StatusUnprocessableEntity).JSON(report)
+	}
+
+	return c.Status(http.StatusCreated).JSON(report)
+}
+
+// GetQuiz will fetch quiz by ID.
 func (controller *quizController) GetQuiz(c *fiber.Ctx) error {
 	quizID, err := uuid.Parse(c.Params("quizID"))
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	quiz, err := controller.service.GetQuiz(quizID)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quiz)
+}
+
+// ListQuizzes will list a page of quizzes matching the filters specified in query parameters.
+func (controller *quizController) ListQuizzes(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	query, err := parseQuizQuery(c, user.ID)
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	err = query.Validate()
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	quizzes, err := controller.service.List(query, c.Query("cursor"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quizzes)
+}
+
+// UpdateQuiz will replace title, time and questions of quiz.
+func (controller *quizController) UpdateQuiz(c *fiber.Ctx) error {
+	quiz := models.Quiz{}
+
+	err := c.BodyParser(&quiz)
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	quiz.ID, err = uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	err = quiz.Validate()
+	if err != nil {
+		return err
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	err = controller.service.Update(&quiz, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quiz)
+}
+
+// PatchQuiz will update title, time or archive state of quiz.
+func (controller *quizController) PatchQuiz(c *fiber.Ctx) error {
+	patch := models.QuizPatch{}
+
+	err := c.BodyParser(&patch)
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	err = patch.Validate()
+	if err != nil {
+		return err
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quiz, err := controller.service.Patch(quizID, &patch, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quiz)
+}
+
+// DeleteQuiz will delete quiz which has not been attempted by any user.
+func (controller *quizController) DeleteQuiz(c *fiber.Ctx) error {
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	err = controller.service.Delete(quizID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// GetQuizVersions will return every version of quiz.
+func (controller *quizController) GetQuizVersions(c *fiber.Ctx) error {
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizzes, err := controller.service.GetQuizVersions(quizID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quizzes)
+}
+
+// GetQuizVersion will return quiz as it was at specified version.
+func (controller *quizController) GetQuizVersion(c *fiber.Ctx) error {
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	version, err := strconv.ParseUint(c.Params("version"), 10, 32)
+	if err != nil {
+		return serv.InvalidRequest(errors.New("version must be a positive number"))
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quiz, err := controller.service.GetQuizVersion(quizID, uint32(version), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quiz)
+}
+
+// DiffQuizVersions will return changes made to quiz between versions specified by from and to query parameters.
+func (controller *quizController) DiffQuizVersions(c *fiber.Ctx) error {
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	fromVersion, err := strconv.ParseUint(c.Query("from"), 10, 32)
+	if err != nil {
+		return serv.InvalidRequest(errors.New("from must be a positive number"))
+	}
+
+	toVersion, err := strconv.ParseUint(c.Query("to"), 10, 32)
+	if err != nil {
+		return serv.InvalidRequest(errors.New("to must be a positive number"))
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	diff, err := controller.service.DiffQuizVersions(quizID, uint32(fromVersion), uint32(toVersion), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(diff)
+}
+
+// parseQuizQuery will read filters, sorting and limit of quiz listing from query parameters.
+// Owner "me" and attempted filter refer to the logged in user.
+func parseQuizQuery(c *fiber.Ctx, userID uuid.UUID) (*models.QuizQuery, error) {
+	query := &models.QuizQuery{
+		Search: c.Query("search"),
+		Tag:    c.Query("tag"),
+		SortBy: c.Query("sort"),
+		Order:  c.Query("order"),
+	}
+
+	if owner := c.Query("owner"); owner != "" {
+		ownerID := userID
+		if owner != "me" {
+			var err error
+			ownerID, err = uuid.Parse(owner)
+			if err != nil {
+				return nil, errors.New("owner must be a valid ID or me")
+			}
+		}
+		query.CreatedBy = &ownerID
+	}
+
+	if archived := c.Query("archived"); archived != "" {
+		isArchived, err := strconv.ParseBool(archived)
+		if err != nil {
+			return nil, errors.New("archived must be either true or false")
+		}
+		query.IsArchived = &isArchived
+	}
+
+	if attempted := c.Query("attempted"); attempted != "" {
+		hasAttempted, err := strconv.ParseBool(attempted)
+		if err != nil {
+			return nil, errors.New("attempted must be either true or false")
+		}
+		query.AttemptedBy = &userID
+		query.HasAttempted = &hasAttempted
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return nil, errors.New("limit must be a number")
+		}
 	}
 
-	return c.Status(http.StatusCreated).JSON(quiz)
+	return query, nil
 }
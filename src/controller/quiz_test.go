@@ -3,7 +3,8 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/db/models"
 	"github.com/shaileshhb/quiz/src/log"
+	serv "github.com/shaileshhb/quiz/src/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -33,13 +35,62 @@ func (s *MockService) GetQuiz(quizID uuid.UUID) (*models.Quiz, error) {
 	return args.Get(0).(*models.Quiz), args.Error(1)
 }
 
+func (s *MockService) List(query *models.QuizQuery, cursor string) (*models.QuizList, error) {
+	args := s.Called(query, cursor)
+	return args.Get(0).(*models.QuizList), args.Error(1)
+}
+
+func (s *MockService) Update(quiz *models.Quiz, userID uuid.UUID) error {
+	args := s.Called(quiz, userID)
+	return args.Error(0)
+}
+
+func (s *MockService) Patch(quizID uuid.UUID, patch *models.QuizPatch, userID uuid.UUID) (*models.Quiz, error) {
+	args := s.Called(quizID, patch, userID)
+	return args.Get(0).(*models.Quiz), args.Error(1)
+}
+
+func (s *MockService) Delete(quizID, userID uuid.UUID) error {
+	args := s.Called(quizID, userID)
+	return args.Error(0)
+}
+
+func (s *MockService) GetQuizVersions(quizID, userID uuid.UUID) ([]models.Quiz, error) {
+	args := s.Called(quizID, userID)
+	return args.Get(0).([]models.Quiz), args.Error(1)
+}
+
+func (s *MockService) GetQuizVersion(quizID uuid.UUID, version uint32, userID uuid.UUID) (*models.Quiz, error) {
+	args := s.Called(quizID, version, userID)
+	return args.Get(0).(*models.Quiz), args.Error(1)
+}
+
+func (s *MockService) DiffQuizVersions(quizID uuid.UUID, fromVersion, toVersion uint32, userID uuid.UUID) (*models.QuizDiff, error) {
+	args := s.Called(quizID, fromVersion, toVersion, userID)
+	return args.Get(0).(*models.QuizDiff), args.Error(1)
+}
+
+func (s *MockService) Import(format string, content io.Reader, title string, userID uuid.UUID) (*models.ImportReport, error) {
+	args := s.Called(format, content, title, userID)
+	return args.Get(0).(*models.ImportReport), args.Error(1)
+}
+
+// mockAuthMiddleware will set logged in user like security.MandatoryAuthMiddleware.
+func mockAuthMiddleware(userID uuid.UUID) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &models.User{ID: userID})
+		return c.Next()
+	}
+}
+
 func TestCreateQuiz(t *testing.T) {
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
 
 	mockService := new(MockService)
 
 	quizController := NewQuizController(mockService, logger)
 
+	app.Use(mockAuthMiddleware(uuid.New()))
 	app.Post("/quizzes", quizController.CreateQuiz)
 
 	t.Run("Invalid Request Body", func(t *testing.T) {
@@ -61,8 +112,18 @@ func TestCreateQuiz(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		resp, _ := app.Test(req)
 
-		// Assert the response status is 400 BadRequest
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		// Assert the response status is 422 UnprocessableEntity listing every invalid field
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		response := ErrorResponse{}
+		err := json.NewDecoder(resp.Body).Decode(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, "validation_failed", response.Error.Code)
+		assert.Equal(t, "title must be specified", response.Error.Message)
+		assert.Equal(t, []models.Violation{
+			{Field: "title", Code: models.ViolationRequired, Message: "title must be specified"},
+			{Field: "questions", Code: models.ViolationRequired, Message: "at least one question is required"},
+		}, response.Error.Violations)
 	})
 
 	t.Run("Service Error", func(t *testing.T) {
@@ -96,14 +157,255 @@ func TestCreateQuiz(t *testing.T) {
 
 		quizBytes, _ := json.Marshal(&quizOne)
 
-		mockService.On("Create", mock.Anything).Return(errors.New("service error")).Once()
+		mockService.On("Create", mock.Anything).Return(serv.ErrQuizTitleExists).Once()
 
 		req := httptest.NewRequest(http.MethodPost, "/quizzes", bytes.NewBuffer(quizBytes))
 		req.Header.Set("Content-Type", "application/json")
 		resp, _ := app.Test(req)
 
+		// Assert the response status is 409 Conflict
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestPatchQuiz(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+
+	mockService := new(MockService)
+	userID := uuid.New()
+
+	quizController := NewQuizController(mockService, logger)
+
+	app.Use(mockAuthMiddleware(userID))
+	app.Patch("/quizzes/:quizID", quizController.PatchQuiz)
+
+	t.Run("Empty Patch", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/quizzes/"+uuid.NewString(), bytes.NewBuffer([]byte("{}")))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		// Assert the response status is 422 UnprocessableEntity
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		quizID := uuid.New()
+
+		mockService.On("Patch", quizID, mock.Anything, userID).Return(&models.Quiz{ID: quizID, IsArchived: true}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPatch, "/quizzes/"+quizID.String(), bytes.NewBuffer([]byte(`{"isArchived": true}`)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestDeleteQuiz(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+
+	mockService := new(MockService)
+	userID := uuid.New()
+
+	quizController := NewQuizController(mockService, logger)
+
+	app.Use(mockAuthMiddleware(userID))
+	app.Delete("/quizzes/:quizID", quizController.DeleteQuiz)
+
+	t.Run("Invalid Quiz ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/quizzes/invalid", nil)
+		resp, _ := app.Test(req)
+
 		// Assert the response status is 400 BadRequest
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		quizID := uuid.New()
+
+		mockService.On("Delete", quizID, userID).Return(serv.ErrNotQuizCreator).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/quizzes/"+quizID.String(), nil)
+		resp, _ := app.Test(req)
+
+		// Assert the response status is 403 Forbidden
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		quizID := uuid.New()
+
+		mockService.On("Delete", quizID, userID).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/quizzes/"+quizID.String(), nil)
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestDiffQuizVersions(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+
+	mockService := new(MockService)
+	userID := uuid.New()
+
+	quizController := NewQuizController(mockService, logger)
+
+	app.Use(mockAuthMiddleware(userID))
+	app.Get("/quizzes/:quizID/diff", quizController.DiffQuizVersions)
+
+	t.Run("Invalid Version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quizzes/"+uuid.NewString()+"/diff?from=one&to=2", nil)
+		resp, _ := app.Test(req)
+
+		// Assert the response status is 400 BadRequest
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		quizID := uuid.New()
+
+		mockService.On("DiffQuizVersions", quizID, uint32(1), uint32(2), userID).Return(&models.QuizDiff{}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/quizzes/"+quizID.String()+"/diff?from=1&to=2", nil)
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestListQuizzes(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+
+	mockService := new(MockService)
+	userID := uuid.New()
+
+	quizController := NewQuizController(mockService, logger)
+
+	app.Use(mockAuthMiddleware(userID))
+	app.Get("/quizzes", quizController.ListQuizzes)
+
+	t.Run("Invalid Sort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quizzes?sort=answers", nil)
+		resp, _ := app.Test(req)
+
+		// Assert the response status is 400 BadRequest
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Invalid Filter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quizzes?attempted=maybe", nil)
+		resp, _ := app.Test(req)
+
+		// Assert the response status is 400 BadRequest
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		trueValue := true
+		expectedQuery := &models.QuizQuery{
+			Search:       "capital",
+			CreatedBy:    &userID,
+			AttemptedBy:  &userID,
+			HasAttempted: &trueValue,
+			SortBy:       models.SortByTitle,
+			Order:        models.OrderAsc,
+			Limit:        5,
+		}
+
+		mockService.On("List", expectedQuery, "cursor").Return(&models.QuizList{}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/quizzes?search=capital&owner=me&attempted=true&sort=title&limit=5&cursor=cursor", nil)
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+// newMultipartRequest will create request uploading content as file field of multipart form along with given fields.
+func newMultipartRequest(t *testing.T, url, fileName, content string, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportQuizzes(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+
+	mockService := new(MockService)
+	userID := uuid.New()
+
+	quizController := NewQuizController(mockService, logger)
+
+	app.Use(mockAuthMiddleware(userID))
+	app.Post("/quizzes/import", quizController.ImportQuizzes)
+
+	t.Run("Missing File", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quizzes/import", bytes.NewBuffer([]byte("{}")))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Format From File Name", func(t *testing.T) {
+		report := &models.ImportReport{Quizzes: []models.QuizSummary{{ID: uuid.New()}}}
+		mockService.On("Import", "gift", mock.Anything, "Capitals", userID).Return(report, nil).Once()
+
+		req := newMultipartRequest(t, "/quizzes/import", "Capitals.gift", "Capital of France? {=Paris ~London}", nil)
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error Report", func(t *testing.T) {
+		report := &models.ImportReport{
+			Quizzes: []models.QuizSummary{},
+			Errors:  []models.ImportError{{Line: 2, Quiz: "Geography", Error: "text must be specified"}},
+		}
+		mockService.On("Import", "csv", mock.Anything, "Geography", userID).Return(report, nil).Once()
+
+		req := newMultipartRequest(t, "/quizzes/import", "questions.txt", "question\n\"\"", map[string]string{
+			"format": "csv",
+			"title":  "Geography",
+		})
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		responseReport := models.ImportReport{}
+		json.NewDecoder(resp.Body).Decode(&responseReport)
+		assert.Equal(t, report.Errors, responseReport.Errors)
 
 		mockService.AssertExpectations(t)
 	})
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/security"
+	serv "github.com/shaileshhb/quiz/src/service"
+)
+
+// quizExportController contains reference to quiz export service and logger
+type quizExportController struct {
+	service serv.QuizExportService
+	log     zerolog.Logger
+}
+
+// NewQuizExportController will create new instance of quizExportController.
+func NewQuizExportController(service serv.QuizExportService, log zerolog.Logger) *quizExportController {
+	return &quizExportController{
+		service: service,
+		log:     log,
+	}
+}
+
+// RegisterRoute registers all endpoints to router.
+func (controller *quizExportController) RegisterRoute(router fiber.Router) {
+	router.Get("/quizzes/:quizID/export", security.MandatoryAuthMiddleware, controller.ExportQuiz)
+
+	controller.log.Info().Msg("Quiz export routes registered")
+}
+
+// ExportQuiz will send quiz as a file of format given by "format" query parameter, which is qti, json or gift.
+func (controller *quizExportController) ExportQuiz(c *fiber.Ctx) error {
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	export, err := controller.service.Export(quizID, c.Query("format"), user.ID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+export.FileName+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(http.StatusOK).Send(export.Content)
+}
@@ -36,18 +36,12 @@ func (controller *userController) register(c *fiber.Ctx) error {
 
 	err := c.BodyParser(user)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	loginResponse, err := controller.service.Register(user)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusCreated).JSON(loginResponse)
@@ -59,18 +53,12 @@ func (controller *userController) login(c *fiber.Ctx) error {
 
 	err := c.BodyParser(login)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	loginResponse, err := controller.service.Login(login)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(loginResponse)
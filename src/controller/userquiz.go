@@ -29,7 +29,12 @@ func NewUserQuizController(service serv.UserQuizService, log zerolog.Logger) *us
 func (controller *userQuizController) RegisterRoute(router fiber.Router) {
 	router.Post("/users/quizzes/:quizID/start", security.MandatoryAuthMiddleware, controller.startQuiz)
 	router.Post("/users/quizzes/:quizID/attempts/:attemptID", security.MandatoryAuthMiddleware, controller.submitAnswer)
+	router.Post("/users/quizzes/:quizID/attempts/:attemptID/finish", security.MandatoryAuthMiddleware, controller.finishAttempt)
 	router.Get("/users/quizzes/:quizID/results", security.MandatoryAuthMiddleware, controller.getUserQuizResults)
+	router.Get("/users/quizzes/:quizID/attempts", security.MandatoryAuthMiddleware, controller.listAttempts)
+	router.Get("/users/quizzes/:quizID/attempts/current", security.MandatoryAuthMiddleware, controller.getCurrentAttempt)
+	router.Get("/users/quizzes/:quizID/attempts/:attemptID/results", security.MandatoryAuthMiddleware, controller.getAttemptResults)
+	router.Get("/users/quizzes/:quizID/attempts/:attemptID/questions", security.MandatoryAuthMiddleware, controller.getAttemptQuestions)
 	controller.log.Info().Msg("User quiz routes registered")
 }
 
@@ -39,10 +44,7 @@ func (controller *userQuizController) startQuiz(c *fiber.Ctx) error {
 
 	quizID, err := uuid.Parse(c.Params("quizID"))
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	userInterface := c.Locals("user")
@@ -52,18 +54,12 @@ func (controller *userQuizController) startQuiz(c *fiber.Ctx) error {
 
 	err = userQuiz.Validate()
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	err = controller.service.StartQuiz(&userQuiz)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusCreated).JSON(userQuiz)
@@ -75,53 +71,59 @@ func (controller *userQuizController) submitAnswer(c *fiber.Ctx) error {
 
 	err := c.BodyParser(&userResponse)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	userResponse.QuizID, err = uuid.Parse(c.Params("quizID"))
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	userResponse.UserQuizAttemptID, err = uuid.Parse(c.Params("attemptID"))
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	userInterface := c.Locals("user")
 	user := userInterface.(*models.User)
 	userResponse.UserID = user.ID
-	userResponse.IsCorrect = false
+	userResponse.IsCorrect = nil
+	userResponse.Score = nil
 
 	err = userResponse.Validate()
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
-	correctOption, err := controller.service.SubmitAnswer(&userResponse)
+	answerResult, err := controller.service.SubmitAnswer(&userResponse)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(http.StatusCreated).JSON(map[string]interface{}{
-		"isCorrect":     userResponse.IsCorrect,
-		"correctOption": correctOption,
-	})
+	return c.Status(http.StatusCreated).JSON(answerResult)
+}
+
+// finishAttempt will end user's attempt before all questions are answered and return its final result.
+func (controller *userQuizController) finishAttempt(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	attemptID, err := uuid.Parse(c.Params("attemptID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	result, err := controller.service.FinishAttempt(user.ID, quizID, attemptID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(result)
 }
 
 // getUserQuizResults will return results for specific quiz for specified user.
@@ -131,19 +133,95 @@ func (controller *userQuizController) getUserQuizResults(c *fiber.Ctx) error {
 
 	quizID, err := uuid.Parse(c.Params("quizID"))
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return serv.InvalidRequest(err)
 	}
 
 	userQuiz, err := controller.service.GetUserQuizResults(user.ID, quizID)
 	if err != nil {
-		controller.log.Error().Err(err).Msg("")
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(userQuiz)
 }
+
+// listAttempts will return all attempts of specific quiz made by user along with their final result.
+func (controller *userQuizController) listAttempts(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	attempts, err := controller.service.ListAttempts(user.ID, quizID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(attempts)
+}
+
+// getAttemptResults will return results of specific attempt made by user.
+func (controller *userQuizController) getAttemptResults(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	attemptID, err := uuid.Parse(c.Params("attemptID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	result, err := controller.service.GetAttemptResults(user.ID, quizID, attemptID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(result)
+}
+
+// getCurrentAttempt will return attempt of specific quiz which user has not ended yet.
+func (controller *userQuizController) getCurrentAttempt(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	currentAttempt, err := controller.service.GetCurrentAttempt(user.ID, quizID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(currentAttempt)
+}
+
+// getAttemptQuestions will return questions of quiz in the order they are shown in specific attempt made by user.
+func (controller *userQuizController) getAttemptQuestions(c *fiber.Ctx) error {
+	userInterface := c.Locals("user")
+	user := userInterface.(*models.User)
+
+	quizID, err := uuid.Parse(c.Params("quizID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	attemptID, err := uuid.Parse(c.Params("attemptID"))
+	if err != nil {
+		return serv.InvalidRequest(err)
+	}
+
+	quiz, err := controller.service.GetAttemptQuestions(user.ID, quizID, attemptID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(quiz)
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	serv "github.com/shaileshhb/quiz/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserQuizNotFound will test that missing users and quizzes are reported with the same codes by every endpoint.
+func TestUserQuizNotFound(t *testing.T) {
+	database := db.NewDatabase()
+	userQuizController := NewUserQuizController(serv.NewUserQuizService(database), logger)
+
+	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+	missingID := uuid.New()
+	attemptID := uuid.New()
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		method string
+		url    string
+		code   string
+	}{
+		{"Start Missing Quiz", userID, http.MethodPost, "/users/quizzes/" + missingID.String() + "/start", "quiz_not_found"},
+		{"Results Of Missing Quiz", userID, http.MethodGet, "/users/quizzes/" + missingID.String() + "/results", "quiz_not_found"},
+		{"Current Attempt Of Missing Quiz", userID, http.MethodGet, "/users/quizzes/" + missingID.String() + "/attempts/current", "quiz_not_found"},
+		{"Attempt Results Of Missing Quiz", userID, http.MethodGet, "/users/quizzes/" + missingID.String() + "/attempts/" + attemptID.String() + "/results", "quiz_not_found"},
+		{"Finish Attempt Of Missing Quiz", userID, http.MethodPost, "/users/quizzes/" + missingID.String() + "/attempts/" + attemptID.String() + "/finish", "quiz_not_found"},
+		{"Start By Missing User", missingID, http.MethodPost, "/users/quizzes/" + quizID.String() + "/start", "user_not_found"},
+		{"Results Of Missing User", missingID, http.MethodGet, "/users/quizzes/" + quizID.String() + "/results", "user_not_found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+			app.Use(mockAuthMiddleware(test.userID))
+			app.Post("/users/quizzes/:quizID/start", userQuizController.startQuiz)
+			app.Post("/users/quizzes/:quizID/attempts/:attemptID/finish", userQuizController.finishAttempt)
+			app.Get("/users/quizzes/:quizID/results", userQuizController.getUserQuizResults)
+			app.Get("/users/quizzes/:quizID/attempts/current", userQuizController.getCurrentAttempt)
+			app.Get("/users/quizzes/:quizID/attempts/:attemptID/results", userQuizController.getAttemptResults)
+
+			resp, _ := app.Test(httptest.NewRequest(test.method, test.url, nil))
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			response := ErrorResponse{}
+			err := json.NewDecoder(resp.Body).Decode(&response)
+			assert.Nil(t, err)
+			assert.Equal(t, test.code, response.Error.Code)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// cloneQuiz will deep copy quiz so that callers can not modify stored records.
+func cloneQuiz(q models.Quiz) models.Quiz {
+	if q.Tags != nil {
+		q.Tags = append([]string(nil), q.Tags...)
+	}
+
+	if q.Pools != nil {
+		q.Pools = append([]models.QuestionPool(nil), q.Pools...)
+	}
+
+	if q.Questions != nil {
+		questions := make([]models.Question, len(q.Questions))
+		for i, question := range q.Questions {
+			questions[i] = cloneQuestion(question)
+		}
+		q.Questions = questions
+	}
+
+	return q
+}
+
+// cloneBankQuestion will deep copy question of question bank along with its tags.
+func cloneBankQuestion(q models.BankQuestion) models.BankQuestion {
+	if q.Tags != nil {
+		q.Tags = append([]string(nil), q.Tags...)
+	}
+
+	q.Question = cloneQuestion(q.Question)
+	return q
+}
+
+// cloneQuestion will deep copy question along with its options and answers.
+func cloneQuestion(q models.Question) models.Question {
+	if q.AcceptedAnswers != nil {
+		q.AcceptedAnswers = append([]string(nil), q.AcceptedAnswers...)
+	}
+
+	if q.NumericAnswer != nil {
+		numericAnswer := *q.NumericAnswer
+		q.NumericAnswer = &numericAnswer
+	}
+
+	if q.BankQuestionID != nil {
+		bankQuestionID := *q.BankQuestionID
+		q.BankQuestionID = &bankQuestionID
+	}
+
+	if q.MediaIDs != nil {
+		q.MediaIDs = append([]uuid.UUID(nil), q.MediaIDs...)
+	}
+
+	if q.Options != nil {
+		options := make([]models.Option, len(q.Options))
+		for i, option := range q.Options {
+			options[i] = cloneOption(option)
+		}
+		q.Options = options
+	}
+
+	return q
+}
+
+// cloneOption will deep copy option.
+func cloneOption(o models.Option) models.Option {
+	if o.IsCorrect != nil {
+		isCorrect := *o.IsCorrect
+		o.IsCorrect = &isCorrect
+	}
+
+	if o.MediaIDs != nil {
+		o.MediaIDs = append([]uuid.UUID(nil), o.MediaIDs...)
+	}
+
+	return o
+}
+
+// cloneAttempt will deep copy attempt along with its responses.
+func cloneAttempt(a models.UserQuizAttempts) models.UserQuizAttempts {
+	if a.StartedAt != nil {
+		startedAt := *a.StartedAt
+		a.StartedAt = &startedAt
+	}
+
+	if a.EndedAt != nil {
+		endedAt := *a.EndedAt
+		a.EndedAt = &endedAt
+	}
+
+	if a.ExpiresAt != nil {
+		expiresAt := *a.ExpiresAt
+		a.ExpiresAt = &expiresAt
+	}
+
+	if a.QuestionIDs != nil {
+		a.QuestionIDs = append([]uuid.UUID(nil), a.QuestionIDs...)
+	}
+
+	if a.UserResponses != nil {
+		responses := make([]models.UserResponse, len(a.UserResponses))
+		for i, response := range a.UserResponses {
+			responses[i] = cloneResponse(response)
+		}
+		a.UserResponses = responses
+	}
+
+	return a
+}
+
+// cloneResponse will deep copy answer of user response.
+func cloneResponse(r models.UserResponse) models.UserResponse {
+	if r.SelectedOptionIDs != nil {
+		r.SelectedOptionIDs = append([]uuid.UUID(nil), r.SelectedOptionIDs...)
+	}
+
+	if r.NumericAnswer != nil {
+		numericAnswer := *r.NumericAnswer
+		r.NumericAnswer = &numericAnswer
+	}
+
+	if r.BankQuestionID != nil {
+		bankQuestionID := *r.BankQuestionID
+		r.BankQuestionID = &bankQuestionID
+	}
+
+	return r
+}
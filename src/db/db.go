@@ -1,40 +1,691 @@
 package db
 
 import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/db/models"
 	"github.com/shaileshhb/quiz/src/security"
 )
 
-// Database will mimic a database
+// userQuizKey is the secondary index key for attempts of a quiz made by a user.
+type userQuizKey struct {
+	userID uuid.UUID
+	quizID uuid.UUID
+}
+
+// Database will mimic a database by keeping all records in memory.
+// Records are indexed by their ID along with secondary indexes for every lookup done by the services.
+// mu is only held while reading or writing records and never across service calls,
+// attempts are updated using compare-and-swap on their version instead.
 type Database struct {
-	Quiz             []models.Quiz
-	Users            []models.User
-	UserQuizAttempts []models.UserQuizAttempts
+	mu       sync.RWMutex
+	quizzes  map[uuid.UUID]models.Quiz
+	users    map[uuid.UUID]models.User
+	attempts map[uuid.UUID]models.UserQuizAttempts
+
+	bankQuestions map[uuid.UUID]models.BankQuestion
+	media         map[uuid.UUID]models.Media
+
+	quizIDByTitle        map[string]uuid.UUID        // lower-cased title to quizID
+	userIDByUsername     map[string]uuid.UUID        // lower-cased username to userID
+	attemptIDsByUserQuiz map[userQuizKey][]uuid.UUID // sorted by attempt number
+	attemptCountByQuiz   map[uuid.UUID]int
+	unfinishedAttempts   map[uuid.UUID]bool // IDs of attempts which have not ended
+
+	// attemptIDsByBankQuestion contains IDs of attempts having responses for questions copied from a bank question.
+	attemptIDsByBankQuestion map[uuid.UUID]map[uuid.UUID]bool
+
+	// quizVersions contains immutable snapshot of every version of a quiz, keyed by quizID and version.
+	quizVersions map[uuid.UUID]map[uint32]models.Quiz
+
+	// quizIDsByMedia contains IDs of quizzes having a version which attaches the media.
+	quizIDsByMedia map[uuid.UUID]map[uuid.UUID]bool
+
+	// wal is nil unless database was opened using OpenDatabase.
+	wal *writeAheadLog
 }
 
-// NewDatabase will initialize a new database instance
+// NewDatabase will initialize a new in-memory database instance seeded with dummy data.
+// All records are lost once the process exits, use OpenDatabase to persist them.
 func NewDatabase() *Database {
-	db := &Database{
-		Quiz: []models.Quiz{},
+	db := newDatabase()
+	seed(db)
+
+	return db
+}
+
+// newDatabase will initialize an empty in-memory database.
+func newDatabase() *Database {
+	return &Database{
+		quizzes:              map[uuid.UUID]models.Quiz{},
+		users:                map[uuid.UUID]models.User{},
+		attempts:             map[uuid.UUID]models.UserQuizAttempts{},
+		bankQuestions:        map[uuid.UUID]models.BankQuestion{},
+		media:                map[uuid.UUID]models.Media{},
+		quizIDByTitle:        map[string]uuid.UUID{},
+		userIDByUsername:     map[string]uuid.UUID{},
+		attemptIDsByUserQuiz: map[userQuizKey][]uuid.UUID{},
+		attemptCountByQuiz:   map[uuid.UUID]int{},
+		unfinishedAttempts:   map[uuid.UUID]bool{},
+
+		attemptIDsByBankQuestion: map[uuid.UUID]map[uuid.UUID]bool{},
+		quizVersions:             map[uuid.UUID]map[uint32]models.Quiz{},
+		quizIDsByMedia:           map[uuid.UUID]map[uuid.UUID]bool{},
+	}
+}
+
+// CreateQuiz will add quiz to the database.
+func (db *Database) CreateQuiz(quiz *models.Quiz) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.quizIDByTitle[strings.ToLower(quiz.Title)]; ok {
+		return ErrDuplicateRecord
 	}
 
-	createDummyQuiz(db)
-	createDummyUsers(db)
+	return db.write(logRecord{Op: opQuizCreated, Quiz: quiz})
+}
+
+// GetQuizByID will fetch quiz by given quizID.
+func (db *Database) GetQuizByID(quizID uuid.UUID) (*models.Quiz, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
 
-	return db
+	return db.getQuiz(quizID)
+}
+
+// GetQuizByTitle will fetch quiz by given title, ignoring case.
+func (db *Database) GetQuizByTitle(title string) (*models.Quiz, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	quizID, ok := db.quizIDByTitle[strings.ToLower(title)]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	return db.getQuiz(quizID)
+}
+
+// UpdateQuiz will replace stored quiz with the given quiz if it was not modified since it was read.
+func (db *Database) UpdateQuiz(quiz *models.Quiz) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stored, ok := db.quizzes[quiz.ID]
+	if !ok {
+		return ErrRecordNotFound
+	}
+
+	if stored.Revision != quiz.Revision || (quiz.Version != stored.Version && quiz.Version != stored.Version+1) {
+		return ErrVersionConflict
+	}
+
+	quizID, ok := db.quizIDByTitle[strings.ToLower(quiz.Title)]
+	if ok && quizID != quiz.ID {
+		return ErrDuplicateRecord
+	}
+
+	quiz.Revision++
+
+	err := db.write(logRecord{Op: opQuizUpdated, Quiz: quiz})
+	if err != nil {
+		quiz.Revision--
+		return err
+	}
+
+	return nil
+}
+
+// DeleteQuiz will remove quiz with given quizID.
+func (db *Database) DeleteQuiz(quizID uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	quiz, ok := db.quizzes[quizID]
+	if !ok {
+		return ErrRecordNotFound
+	}
+
+	if db.attemptCountByQuiz[quizID] > 0 {
+		return ErrRecordInUse
+	}
+
+	return db.write(logRecord{Op: opQuizDeleted, Quiz: &models.Quiz{ID: quiz.ID}})
+}
+
+// GetQuizVersion will fetch snapshot of quiz as it was at given version.
+func (db *Database) GetQuizVersion(quizID uuid.UUID, version uint32) (*models.Quiz, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	quiz, ok := db.quizVersions[quizID][version]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	quiz = cloneQuiz(quiz)
+	return &quiz, nil
+}
+
+// ListQuizVersions will fetch snapshots of every version of quiz ordered by version.
+func (db *Database) ListQuizVersions(quizID uuid.UUID) ([]models.Quiz, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	quizzes := make([]models.Quiz, 0, len(db.quizVersions[quizID]))
+	for _, quiz := range db.quizVersions[quizID] {
+		quizzes = append(quizzes, cloneQuiz(quiz))
+	}
+
+	sort.Slice(quizzes, func(i, j int) bool {
+		return quizzes[i].Version < quizzes[j].Version
+	})
+
+	return quizzes, nil
+}
+
+// ListQuizVersionsByMedia will fetch snapshots of every version of quizzes which attaches media.
+func (db *Database) ListQuizVersionsByMedia(mediaID uuid.UUID) ([]models.Quiz, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	quizzes := []models.Quiz{}
+
+	for quizID := range db.quizIDsByMedia[mediaID] {
+		for _, quiz := range db.quizVersions[quizID] {
+			if slices.Contains(quiz.MediaIDs(), mediaID) {
+				quizzes = append(quizzes, cloneQuiz(quiz))
+			}
+		}
+	}
+
+	return quizzes, nil
+}
+
+// ListQuizzes will fetch quizzes matching the query along with total number of matching quizzes.
+func (db *Database) ListQuizzes(query *models.QuizQuery) ([]models.Quiz, int, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var matched []*models.Quiz
+	total := 0
+
+	for quizID := range db.quizzes {
+		quiz := db.quizzes[quizID]
+
+		hasAttempted := func() bool {
+			return len(db.attemptIDsByUserQuiz[userQuizKey{userID: *query.AttemptedBy, quizID: quizID}]) > 0
+		}
+
+		if !matchesQuizQuery(&quiz, query, hasAttempted) {
+			continue
+		}
+
+		total++
+
+		if query.After != nil && compareQuizzes(models.PositionOf(&quiz, query.SortBy), *query.After, query) <= 0 {
+			continue
+		}
+
+		matched = append(matched, &quiz)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return compareQuizzes(models.PositionOf(matched[i], query.SortBy), models.PositionOf(matched[j], query.SortBy), query) < 0
+	})
+
+	if len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+
+	quizzes := make([]models.Quiz, len(matched))
+	for i, quiz := range matched {
+		quizzes[i] = cloneQuiz(*quiz)
+	}
+
+	return quizzes, total, nil
+}
+
+// putQuiz will store copy of quiz and update its indexes, caller must hold mu.
+// Snapshot of the quiz version is stored as well unless it already exists.
+func (db *Database) putQuiz(quiz models.Quiz) {
+	db.removeQuiz(quiz.ID)
+	db.quizzes[quiz.ID] = cloneQuiz(quiz)
+	db.quizIDByTitle[strings.ToLower(quiz.Title)] = quiz.ID
+
+	db.putQuizVersion(quiz)
+}
+
+// putQuizVersion will store snapshot of quiz version unless it already exists, caller must hold mu.
+func (db *Database) putQuizVersion(quiz models.Quiz) {
+	// quizzes created before versioning was introduced do not have a version.
+	if quiz.Version == 0 {
+		return
+	}
+
+	if db.quizVersions[quiz.ID] == nil {
+		db.quizVersions[quiz.ID] = map[uint32]models.Quiz{}
+	}
+
+	if _, ok := db.quizVersions[quiz.ID][quiz.Version]; ok {
+		return
+	}
+
+	db.quizVersions[quiz.ID][quiz.Version] = cloneQuiz(quiz)
+
+	for _, mediaID := range quiz.MediaIDs() {
+		if db.quizIDsByMedia[mediaID] == nil {
+			db.quizIDsByMedia[mediaID] = map[uuid.UUID]bool{}
+		}
+		db.quizIDsByMedia[mediaID][quiz.ID] = true
+	}
+}
+
+// removeQuizVersions will remove snapshots of every version of quiz and their indexes, caller must hold mu.
+func (db *Database) removeQuizVersions(quizID uuid.UUID) {
+	for _, quiz := range db.quizVersions[quizID] {
+		for _, mediaID := range quiz.MediaIDs() {
+			delete(db.quizIDsByMedia[mediaID], quizID)
+			if len(db.quizIDsByMedia[mediaID]) == 0 {
+				delete(db.quizIDsByMedia, mediaID)
+			}
+		}
+	}
+
+	delete(db.quizVersions, quizID)
+}
+
+// removeQuiz will remove quiz and its indexes if it exists, caller must hold mu.
+func (db *Database) removeQuiz(quizID uuid.UUID) {
+	quiz, ok := db.quizzes[quizID]
+	if !ok {
+		return
+	}
+
+	delete(db.quizIDByTitle, strings.ToLower(quiz.Title))
+	delete(db.quizzes, quizID)
+}
+
+// getQuiz will return copy of quiz, caller must hold mu.
+func (db *Database) getQuiz(quizID uuid.UUID) (*models.Quiz, error) {
+	quiz, ok := db.quizzes[quizID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	quiz = cloneQuiz(quiz)
+	return &quiz, nil
+}
+
+// CreateBankQuestion will add question to question bank.
+func (db *Database) CreateBankQuestion(question *models.BankQuestion) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.bankQuestions[question.ID]; ok {
+		return ErrDuplicateRecord
+	}
+
+	return db.write(logRecord{Op: opBankQuestionCreated, BankQuestion: question})
+}
+
+// GetBankQuestionByID will fetch question of question bank by given questionID.
+func (db *Database) GetBankQuestionByID(questionID uuid.UUID) (*models.BankQuestion, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	question, ok := db.bankQuestions[questionID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	question = cloneBankQuestion(question)
+	return &question, nil
+}
+
+// ListBankQuestions will fetch questions of question bank created by given user, optionally having given tag.
+func (db *Database) ListBankQuestions(createdBy uuid.UUID, tag string) ([]models.BankQuestion, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	questions := []models.BankQuestion{}
+
+	for _, question := range db.bankQuestions {
+		if question.CreatedBy != createdBy || (tag != "" && !hasTag(question.Tags, tag)) {
+			continue
+		}
+
+		questions = append(questions, cloneBankQuestion(question))
+	}
+
+	sort.Slice(questions, func(i, j int) bool {
+		if !questions[i].CreatedAt.Equal(questions[j].CreatedAt) {
+			return questions[i].CreatedAt.Before(questions[j].CreatedAt)
+		}
+
+		return questions[i].ID.String() < questions[j].ID.String()
+	})
+
+	return questions, nil
+}
+
+// UpdateBankQuestion will replace stored question of question bank with the given question.
+func (db *Database) UpdateBankQuestion(question *models.BankQuestion) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.bankQuestions[question.ID]; !ok {
+		return ErrRecordNotFound
+	}
+
+	return db.write(logRecord{Op: opBankQuestionUpdated, BankQuestion: question})
+}
+
+// DeleteBankQuestion will remove question of question bank with given questionID.
+func (db *Database) DeleteBankQuestion(questionID uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.bankQuestions[questionID]; !ok {
+		return ErrRecordNotFound
+	}
+
+	return db.write(logRecord{Op: opBankQuestionDeleted, BankQuestion: &models.BankQuestion{
+		Question: models.Question{ID: questionID},
+	}})
+}
+
+// CreateMedia will add details of uploaded media to the database.
+func (db *Database) CreateMedia(media *models.Media) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.media[media.ID]; ok {
+		return ErrDuplicateRecord
+	}
+
+	return db.write(logRecord{Op: opMediaCreated, Media: media})
+}
+
+// GetMediaByID will fetch details of uploaded media by given mediaID.
+func (db *Database) GetMediaByID(mediaID uuid.UUID) (*models.Media, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	media, ok := db.media[mediaID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	return &media, nil
+}
+
+// CreateUser will add user to the database.
+func (db *Database) CreateUser(user *models.User) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.userIDByUsername[strings.ToLower(user.Username)]; ok {
+		return ErrDuplicateRecord
+	}
+
+	return db.write(logRecord{Op: opUserRegistered, User: user})
 }
 
-func createDummyQuiz(db *Database) {
+// putUser will store user and update its indexes, caller must hold mu.
+func (db *Database) putUser(user models.User) {
+	db.users[user.ID] = user
+	db.userIDByUsername[strings.ToLower(user.Username)] = user.ID
+}
+
+// GetUserByID will fetch user by given userID.
+func (db *Database) GetUserByID(userID uuid.UUID) (*models.User, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	user, ok := db.users[userID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	return &user, nil
+}
+
+// GetUserByUsername will fetch user by given username, ignoring case.
+func (db *Database) GetUserByUsername(username string) (*models.User, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	userID, ok := db.userIDByUsername[strings.ToLower(username)]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	user := db.users[userID]
+	return &user, nil
+}
+
+// CreateAttempt will add user quiz attempt to the database.
+func (db *Database) CreateAttempt(attempt *models.UserQuizAttempts) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.quizzes[attempt.QuizID]; !ok {
+		return ErrRecordNotFound
+	}
+
+	for _, attemptID := range db.attemptIDsByUserQuiz[userQuizKey{userID: attempt.UserID, quizID: attempt.QuizID}] {
+		stored := db.attempts[attemptID]
+		if stored.Number() == attempt.Number() {
+			return ErrDuplicateRecord
+		}
+	}
+
+	return db.write(logRecord{Op: opAttemptStarted, Attempt: attempt})
+}
+
+// GetAttemptByID will fetch user quiz attempt by given attemptID.
+func (db *Database) GetAttemptByID(attemptID uuid.UUID) (*models.UserQuizAttempts, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.getAttempt(attemptID)
+}
+
+// GetAttemptByUserAndQuiz will fetch latest attempt of given quiz made by given user.
+func (db *Database) GetAttemptByUserAndQuiz(userID, quizID uuid.UUID) (*models.UserQuizAttempts, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	attemptIDs := db.attemptIDsByUserQuiz[userQuizKey{userID: userID, quizID: quizID}]
+	if len(attemptIDs) == 0 {
+		return nil, ErrRecordNotFound
+	}
+
+	return db.getAttempt(attemptIDs[len(attemptIDs)-1])
+}
+
+// ListAttemptsByUserAndQuiz will fetch all attempts of given quiz made by given user sorted by attempt number.
+func (db *Database) ListAttemptsByUserAndQuiz(userID, quizID uuid.UUID) ([]models.UserQuizAttempts, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	attempts := []models.UserQuizAttempts{}
+
+	for _, attemptID := range db.attemptIDsByUserQuiz[userQuizKey{userID: userID, quizID: quizID}] {
+		attempts = append(attempts, cloneAttempt(db.attempts[attemptID]))
+	}
+
+	return attempts, nil
+}
+
+// CountAttemptsByQuiz will return number of attempts made for given quiz by all users.
+func (db *Database) CountAttemptsByQuiz(quizID uuid.UUID) (int, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.attemptCountByQuiz[quizID], nil
+}
+
+// ListResponsesByBankQuestion will fetch responses of all attempts submitted for questions copied from given bank question.
+func (db *Database) ListResponsesByBankQuestion(bankQuestionID uuid.UUID) ([]models.UserResponse, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	responses := []models.UserResponse{}
+
+	for attemptID := range db.attemptIDsByBankQuestion[bankQuestionID] {
+		for _, response := range db.attempts[attemptID].UserResponses {
+			if response.BankQuestionID != nil && *response.BankQuestionID == bankQuestionID {
+				responses = append(responses, cloneResponse(response))
+			}
+		}
+	}
+
+	return responses, nil
+}
+
+// putAttempt will store copy of attempt and update its indexes, caller must hold mu.
+func (db *Database) putAttempt(attempt models.UserQuizAttempts) {
+	_, ok := db.attempts[attempt.ID]
+	db.attempts[attempt.ID] = cloneAttempt(attempt)
+
+	if !ok {
+		db.attemptCountByQuiz[attempt.QuizID]++
+
+		// attempts are not restored from a snapshot in order, so the index is kept sorted on every insert.
+		key := userQuizKey{userID: attempt.UserID, quizID: attempt.QuizID}
+		attemptIDs := append(db.attemptIDsByUserQuiz[key], attempt.ID)
+		sort.SliceStable(attemptIDs, func(i, j int) bool {
+			first, second := db.attempts[attemptIDs[i]], db.attempts[attemptIDs[j]]
+			return first.Number() < second.Number()
+		})
+		db.attemptIDsByUserQuiz[key] = attemptIDs
+	}
+
+	if attempt.EndedAt == nil {
+		db.unfinishedAttempts[attempt.ID] = true
+	} else {
+		delete(db.unfinishedAttempts, attempt.ID)
+	}
+
+	// responses are never removed from an attempt, so attempts are only added to the index.
+	for _, response := range attempt.UserResponses {
+		if response.BankQuestionID == nil {
+			continue
+		}
+
+		attemptIDs := db.attemptIDsByBankQuestion[*response.BankQuestionID]
+		if attemptIDs == nil {
+			attemptIDs = map[uuid.UUID]bool{}
+			db.attemptIDsByBankQuestion[*response.BankQuestionID] = attemptIDs
+		}
+		attemptIDs[attempt.ID] = true
+	}
+}
+
+// getAttempt will return copy of attempt, caller must hold mu.
+func (db *Database) getAttempt(attemptID uuid.UUID) (*models.UserQuizAttempts, error) {
+	attempt, ok := db.attempts[attemptID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	attempt = cloneAttempt(attempt)
+	return &attempt, nil
+}
+
+// UpdateAttempt will replace stored attempt with the given attempt if it was not modified since it was read.
+func (db *Database) UpdateAttempt(attempt *models.UserQuizAttempts) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stored, ok := db.attempts[attempt.ID]
+	if !ok {
+		return ErrRecordNotFound
+	}
+
+	if stored.Version != attempt.Version {
+		return ErrVersionConflict
+	}
+
+	attempt.Version++
+
+	err := db.write(logRecord{Op: opAttemptUpdated, Attempt: attempt})
+	if err != nil {
+		attempt.Version--
+		return err
+	}
+
+	return nil
+}
+
+// ListExpiredAttempts will fetch attempts which have not ended and expire at or before given time.
+func (db *Database) ListExpiredAttempts(before time.Time) ([]models.UserQuizAttempts, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	attempts := []models.UserQuizAttempts{}
+
+	for attemptID := range db.unfinishedAttempts {
+		attempt := db.attempts[attemptID]
+
+		if attempt.ExpiresAt == nil || !attempt.ExpiresAt.After(before) {
+			attempts = append(attempts, cloneAttempt(attempt))
+		}
+	}
+
+	return attempts, nil
+}
+
+// Close will close the write-ahead log if database was opened using OpenDatabase.
+func (db *Database) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.wal == nil {
+		return nil
+	}
+
+	return db.wal.close()
+}
+
+// seed will add dummy quiz and users to given repository if they are not present.
+func seed(repo Repository) error {
+	err := createDummyQuiz(repo)
+	if err != nil {
+		return err
+	}
+
+	return createDummyUsers(repo)
+}
+
+func createDummyQuiz(repo Repository) error {
 	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+	createdBy, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
 	quiz := models.Quiz{
-		ID:      quizID,
-		Title:   "Sample Quiz",
-		MaxTime: 1,
+		ID:        quizID,
+		Title:     "Sample Quiz",
+		MaxTime:   1,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		Version:   1,
+		Tags:      []string{"geography"},
+	}
+
+	_, err := repo.GetQuizByID(quizID)
+	if err == nil {
+		return nil
 	}
 
 	quiz.Questions = createDummyQuestions(quiz.ID)
-	db.Quiz = append(db.Quiz, quiz)
+	return repo.CreateQuiz(&quiz)
 }
 
 func createDummyQuestions(quizID uuid.UUID) []models.Question {
@@ -95,7 +746,12 @@ func createDummyOptions(questionID uuid.UUID) []models.Option {
 	return options
 }
 
-func createDummyUsers(db *Database) {
+func createDummyUsers(repo Repository) error {
+	_, err := repo.GetUserByUsername("userone")
+	if err == nil {
+		return nil
+	}
+
 	id, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
 	password, _ := security.HashPassword("userone")
 	user := models.User{
@@ -105,7 +761,10 @@ func createDummyUsers(db *Database) {
 		Password: string(password),
 	}
 
-	db.Users = append(db.Users, user)
+	err = repo.CreateUser(&user)
+	if err != nil {
+		return err
+	}
 
 	password, _ = security.HashPassword("usertwo")
 	user = models.User{
@@ -115,5 +774,5 @@ func createDummyUsers(db *Database) {
 		Password: string(password),
 	}
 
-	db.Users = append(db.Users, user)
+	return repo.CreateUser(&user)
 }
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BankQuestion will contain a question stored in question bank. Questions of the bank can be added to
+// any quiz of their creator, and statistics of their answers are aggregated across all of those quizzes.
+type BankQuestion struct {
+	Question
+	CreatedBy uuid.UUID `json:"createdBy"` // ID of the user who created the question, only they can use or modify it
+	CreatedAt time.Time `json:"createdAt"`
+	Tags      []string  `json:"tags"`
+}
+
+// QuestionStatistics will contain statistics of responses submitted for a question.
+type QuestionStatistics struct {
+	TotalResponses    int     `json:"totalResponses"`
+	TotalCorrect      int     `json:"totalCorrect"`
+	TotalSkipped      int     `json:"totalSkipped"`
+	CorrectPercentage float64 `json:"correctPercentage"` // correct responses as percentage of all responses
+	AverageScore      float64 `json:"averageScore"`
+}
+
+// QuizQuestionStatistics will contain statistics of responses submitted for a question in a single quiz.
+type QuizQuestionStatistics struct {
+	QuizID uuid.UUID `json:"quizID"`
+	QuestionStatistics
+}
+
+// BankQuestionStatistics will contain statistics of responses submitted for a question of question bank
+// across all quizzes using it, along with statistics of every quiz.
+type BankQuestionStatistics struct {
+	BankQuestionID uuid.UUID `json:"bankQuestionID"`
+	QuestionStatistics
+	Quizzes []QuizQuestionStatistics `json:"quizzes"`
+}
+
+// Validate will validate tags and question of bank question, returning a ValidationError listing every invalid field.
+func (q *BankQuestion) Validate() error {
+	v := newValidator()
+
+	if q.BankQuestionID != nil {
+		v.add("bankQuestionID", ViolationConflict, "question of question bank can not refer to another bank question")
+		return v.err()
+	}
+
+	q.Tags = validateTags(v.field("tags"), q.Tags)
+	q.Question.validate(v)
+
+	return v.err()
+}
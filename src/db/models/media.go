@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits of media uploaded as attachments of questions and options.
+const (
+	MaxMediaSize        = 2 << 20 // bytes
+	MaxMediaDimension   = 4096    // maximum width and height of images in pixels
+	MaxQuestionMedia    = 5       // attachments of a single question
+	MaxOptionMedia      = 1       // attachments of a single option
+	maxMediaFileNameLen = 255
+)
+
+// MediaContentTypes are the content types of media which can be uploaded.
+var MediaContentTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+// Media will contain details of a file uploaded by a user, which can be attached to questions and options.
+// Content of the file is kept in file storage under the ID of media.
+type Media struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedBy   uuid.UUID `json:"createdBy"` // ID of the user who uploaded the file, only they can attach it
+	CreatedAt   time.Time `json:"createdAt"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"` // detected from content of the file
+	Size        int64     `json:"size"`        // in bytes
+	Width       int       `json:"width"`       // in pixels
+	Height      int       `json:"height"`      // in pixels
+}
+
+// Validate will validate if media has supported content type, size and dimensions.
+func (m *Media) Validate() error {
+	if len(m.FileName) > maxMediaFileNameLen {
+		return errors.New("file name should not exceed 255 characters")
+	}
+
+	if !isMediaContentType(m.ContentType) {
+		return errors.New("file must be a png, jpeg or gif image")
+	}
+
+	if m.Size == 0 {
+		return errors.New("file must not be empty")
+	}
+
+	if m.Size > MaxMediaSize {
+		return fmt.Errorf("file should not exceed %d MB", MaxMediaSize>>20)
+	}
+
+	if m.Width < 1 || m.Height < 1 || m.Width > MaxMediaDimension || m.Height > MaxMediaDimension {
+		return fmt.Errorf("image width and height should be between 1 and %d pixels", MaxMediaDimension)
+	}
+
+	return nil
+}
+
+// isMediaContentType will check if media of given content type can be uploaded.
+func isMediaContentType(contentType string) bool {
+	for _, mediaContentType := range MediaContentTypes {
+		if contentType == mediaContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateMediaIDs will check that not more than max media are attached and none of them is attached twice.
+func validateMediaIDs(v *validator, mediaIDs []uuid.UUID, max int) {
+	if len(mediaIDs) > max {
+		v.add("", ViolationLength, fmt.Sprintf("at most %d media can be attached", max))
+	}
+
+	attached := map[uuid.UUID]bool{}
+
+	for i, mediaID := range mediaIDs {
+		if mediaID == uuid.Nil || attached[mediaID] {
+			v.index(i).add("", ViolationInvalid, "media IDs must be unique and valid")
+		}
+
+		attached[mediaID] = true
+	}
+}
@@ -1,7 +1,8 @@
 package models
 
 import (
-	"errors"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/utils"
@@ -11,32 +12,40 @@ import (
 type Option struct {
 	ID         uuid.UUID `json:"id"`
 	QuestionID uuid.UUID `json:"questionID"`
-	Answer     string    `json:"answer"`
+	Answer     string    `json:"answer"`               // Markdown
+	AnswerHTML string    `json:"answerHTML,omitempty"` // rendered from answer by the server
 	IsCorrect  *bool     `json:"isCorrect,omitempty"`
-}
 
-// Validate will validate if all fields for a option are correctly specified.
-func (o *Option) Validate() error {
-	if len(o.Answer) == 0 {
-		return errors.New("answer must be specified")
-	}
+	// MediaIDs refer to images uploaded by creator of the question, shown along with the answer.
+	MediaIDs []uuid.UUID `json:"mediaIDs,omitempty"`
 
-	if len(o.Answer) > 200 {
-		return errors.New("answer should not exceed 200 characters")
-	}
+	// Feedback in Markdown on why the option is correct or wrong, shown only once feedback is revealed.
+	Feedback     string `json:"feedback,omitempty"`
+	FeedbackHTML string `json:"feedbackHTML,omitempty"`
+}
 
-	isValid, err := utils.ValidateString(o.Answer, `^[a-zA-Z0-9@$!%*/&\s]+$`)
-	if err != nil {
-		return err
-	}
+// Validate will validate all fields of option, returning a ValidationError listing every invalid field.
+func (o *Option) Validate() error {
+	v := newValidator()
+	o.validate(v)
+	return v.err()
+}
 
-	if !isValid {
-		return errors.New("answer contains invalid characters")
+// validate will add violations of every invalid field of option to v.
+func (o *Option) validate(v *validator) {
+	switch {
+	case len(strings.TrimSpace(o.Answer)) == 0:
+		v.add("answer", ViolationRequired, "answer must be specified")
+	case utf8.RuneCountInString(o.Answer) > 200:
+		v.add("answer", ViolationLength, "answer should not exceed 200 characters")
+	case !utils.ValidateMarkdown(o.Answer):
+		v.add("answer", ViolationInvalid, "answer contains invalid characters")
 	}
 
 	if o.IsCorrect == nil {
-		return errors.New("whether answer is correct or not must be specified")
+		v.add("isCorrect", ViolationRequired, "whether answer is correct or not must be specified")
 	}
 
-	return nil
+	validateMediaIDs(v.field("mediaIDs"), o.MediaIDs, MaxOptionMedia)
+	validateExplanation(v.field("feedback"), "feedback", o.Feedback, 500)
 }
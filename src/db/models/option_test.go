@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -28,7 +29,7 @@ func TestValidateAnswerRegex(t *testing.T) {
 	falseValue := false
 
 	option := Option{
-		Answer:     "# This is invalid answer",
+		Answer:     "This is invalid answer\x00",
 		QuestionID: uuid.New(),
 		IsCorrect:  &falseValue,
 	}
@@ -53,3 +54,19 @@ func TestValidateOptionIsCorrect(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "whether answer is correct or not must be specified", err.Error())
 }
+
+// TestValidateOptionFeedback will test for length of option feedback
+func TestValidateOptionFeedback(t *testing.T) {
+	falseValue := false
+
+	option := Option{
+		Answer:    "London",
+		IsCorrect: &falseValue,
+		Feedback:  strings.Repeat("a", 501),
+	}
+
+	err := option.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "feedback should not exceed 500 characters", err.Error())
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
-	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/utils"
@@ -9,51 +11,99 @@ import (
 
 // Question will contain question details for a quiz
 type Question struct {
-	ID      uuid.UUID `json:"id"`
-	Text    string    `json:"text"`
-	QuizID  uuid.UUID `json:"quizID"`
-	Options []Option  `json:"options"`
+	ID       uuid.UUID `json:"id"`
+	Text     string    `json:"text"`               // Markdown
+	TextHTML string    `json:"textHTML,omitempty"` // rendered from text by the server
+	QuizID   uuid.UUID `json:"quizID"`
+	Type     string    `json:"type"`           // name of a registered QuestionType, default is single choice
+	Points   float64   `json:"points"`         // awarded for a correct answer, default is 1
+	Pool     string    `json:"pool,omitempty"` // name of pool of quiz the question is drawn from, empty if it is always asked
+
+	// Explanation of the correct answer in Markdown, shown to users only once feedback mode of quiz reveals it.
+	Explanation     string `json:"explanation,omitempty"`
+	ExplanationHTML string `json:"explanationHTML,omitempty"`
+
+	// MediaIDs refer to images uploaded by creator of the question, shown along with its text.
+	MediaIDs []uuid.UUID `json:"mediaIDs,omitempty"`
+
+	// BankQuestionID refers to question of question bank which the question was copied from.
+	BankQuestionID *uuid.UUID `json:"bankQuestionID,omitempty"`
+
+	// Options are used by choice questions, AcceptedAnswers by short text questions and
+	// NumericAnswer along with Tolerance by numeric questions.
+	Options         []Option `json:"options"`
+	AcceptedAnswers []string `json:"acceptedAnswers,omitempty"`
+	NumericAnswer   *float64 `json:"numericAnswer,omitempty"`
+	Tolerance       float64  `json:"tolerance,omitempty"`
 }
 
-// Validate will validate if all fields for a question are correctly specified.
+// Validate will validate all fields of question, returning a ValidationError listing every invalid field.
+// Questions referring to question bank are not validated, their fields are copied from the bank when quiz is saved.
 func (q *Question) Validate() error {
-	if len(q.Text) == 0 {
-		return errors.New("text must be specified")
+	v := newValidator()
+	q.validate(v)
+	return v.err()
+}
+
+// validate will add violations of every invalid field of question to v.
+func (q *Question) validate(v *validator) {
+	if q.BankQuestionID != nil {
+		return
 	}
 
-	if len(q.Text) > 500 {
-		return errors.New("text should not exceed 500 characters")
+	switch {
+	case len(strings.TrimSpace(q.Text)) == 0:
+		v.add("text", ViolationRequired, "text must be specified")
+	case utf8.RuneCountInString(q.Text) > 500:
+		v.add("text", ViolationLength, "text should not exceed 500 characters")
+	case !utils.ValidateMarkdown(q.Text):
+		v.add("text", ViolationInvalid, "question text contains invalid characters")
 	}
 
-	isValid, err := utils.ValidateString(q.Text, `^[a-zA-Z0-9@$!%*/?&\s]+$`)
-	if err != nil {
-		return err
+	if q.Points == 0 {
+		q.Points = 1
 	}
 
-	if !isValid {
-		return errors.New("question text contains invalid characters")
+	if q.Points < 0 || q.Points > 1000 {
+		v.add("points", ViolationRange, "points should be between 0 and 1000")
 	}
 
-	if len(q.Options) != 4 || len(q.Options) == 0 {
-		return errors.New("question should have exactly 4 options")
+	validateExplanation(v.field("explanation"), "explanation", q.Explanation, 1000)
+	validateMediaIDs(v.field("mediaIDs"), q.MediaIDs, MaxQuestionMedia)
+
+	if q.Type == "" {
+		q.Type = QuestionTypeSingleChoice
 	}
 
-	isCorrectPresent := false
+	questionType, err := GetQuestionType(q.Type)
+	if err != nil {
+		v.add("type", ViolationUnsupported, err.Error())
+		return
+	}
 
-	for _, option := range q.Options {
-		err := option.Validate()
-		if err != nil {
-			return err
-		}
+	v.merge("", questionType.Validate(q))
+}
 
-		if option.IsCorrect != nil && *option.IsCorrect {
-			isCorrectPresent = true
-		}
+// RenderHTML will render Markdown of question, its explanation and options to sanitized HTML.
+// HTML sent by clients is always replaced, so that only HTML rendered by the server is stored.
+func (q *Question) RenderHTML() {
+	q.TextHTML = utils.RenderMarkdown(q.Text)
+	q.ExplanationHTML = utils.RenderMarkdown(q.Explanation)
+
+	for i := range q.Options {
+		q.Options[i].AnswerHTML = utils.RenderMarkdown(q.Options[i].Answer)
+		q.Options[i].FeedbackHTML = utils.RenderMarkdown(q.Options[i].Feedback)
 	}
+}
 
-	if !isCorrectPresent {
-		return errors.New("atleast one correct option must be present")
+// validateExplanation will check that Markdown explanation shown with feedback has valid length and characters.
+func validateExplanation(v *validator, field, explanation string, maxLength int) {
+	if utf8.RuneCountInString(explanation) > maxLength {
+		v.add("", ViolationLength, fmt.Sprintf("%s should not exceed %d characters", field, maxLength))
+		return
 	}
 
-	return nil
+	if !utils.ValidateMarkdown(explanation) {
+		v.add("", ViolationInvalid, field+" contains invalid characters")
+	}
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -24,7 +25,7 @@ func TestValidateEmptyText(t *testing.T) {
 // TestValidateTextRegex will test for valid characters in text
 func TestValidateTextRegex(t *testing.T) {
 	question := Question{
-		Text:    "# This is question text",
+		Text:    "This is question text\x07",
 		QuizID:  uuid.New(),
 		Options: []Option{},
 	}
@@ -46,7 +47,7 @@ func TestValidateOptions(t *testing.T) {
 	err := question.Validate()
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "question should have exactly 4 options", err.Error())
+	assert.Equal(t, "question should have between 2 and 10 options", err.Error())
 }
 
 // TestValidateOptionsMax will test for valid number of options
@@ -61,26 +62,21 @@ func TestValidateOptionsMax(t *testing.T) {
 			{
 				Answer:    "This is answer 1",
 				IsCorrect: &trueValue,
-			}, {
-				Answer:    "This is answer 2",
-				IsCorrect: &falseValue,
-			}, {
-				Answer:    "This is answer 3",
-				IsCorrect: &falseValue,
-			}, {
-				Answer:    "This is answer 4",
-				IsCorrect: &falseValue,
-			}, {
-				Answer:    "This is answer 5",
-				IsCorrect: &falseValue,
 			},
 		},
 	}
 
+	for len(question.Options) <= 10 {
+		question.Options = append(question.Options, Option{
+			Answer:    "This is another answer",
+			IsCorrect: &falseValue,
+		})
+	}
+
 	err := question.Validate()
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "question should have exactly 4 options", err.Error())
+	assert.Equal(t, "question should have between 2 and 10 options", err.Error())
 }
 
 // TestValidateIsCorrect will test for one correct option exist
@@ -112,3 +108,74 @@ func TestValidateIsCorrect(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "atleast one correct option must be present", err.Error())
 }
+
+// TestValidateExplanation will test for length and valid characters of explanation
+func TestValidateExplanation(t *testing.T) {
+	trueValue := true
+	falseValue := false
+
+	question := Question{
+		Text:        "Capital of France",
+		QuizID:      uuid.New(),
+		Explanation: "Paris has been the capital since 987, it's also the largest city.",
+		Options: []Option{
+			{Answer: "Paris", IsCorrect: &trueValue},
+			{Answer: "London", IsCorrect: &falseValue, Feedback: "London is the capital of the UK."},
+		},
+	}
+
+	err := question.Validate()
+	assert.Nil(t, err)
+
+	question.Explanation = "Paris\x1b[31m"
+	err = question.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "explanation contains invalid characters", err.Error())
+
+	question.Explanation = strings.Repeat("a", 1001)
+	err = question.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "explanation should not exceed 1000 characters", err.Error())
+}
+
+// TestValidateMarkdownText will test that question and options can contain Markdown, punctuation and Unicode
+func TestValidateMarkdownText(t *testing.T) {
+	trueValue := true
+	falseValue := false
+
+	question := Question{
+		Text:   "What's **2 + 2**? Écrivez `x := 2 + 2` en Go — 答えは?",
+		QuizID: uuid.New(),
+		Options: []Option{
+			{Answer: "4, \"four\"", IsCorrect: &trueValue},
+			{Answer: "<script>alert(1)</script>", IsCorrect: &falseValue},
+		},
+	}
+
+	err := question.Validate()
+	assert.Nil(t, err)
+
+	question.RenderHTML()
+
+	assert.Equal(t, "<p>What&#39;s <strong>2 + 2</strong>? Écrivez <code>x := 2 + 2</code> en Go — 答えは?</p>", question.TextHTML)
+	assert.NotContains(t, question.Options[1].AnswerHTML, "<script>")
+}
+
+// TestValidateQuestionMedia will test that media is attached to a question at most once
+func TestValidateQuestionMedia(t *testing.T) {
+	mediaID := uuid.New()
+	trueValue := true
+
+	question := Question{
+		Text:     "Which country is highlighted on the map?",
+		MediaIDs: []uuid.UUID{mediaID, mediaID},
+		Options:  []Option{{Answer: "India", IsCorrect: &trueValue}},
+	}
+
+	err := question.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "media IDs must be unique and valid", err.Error())
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"strings"
+)
+
+// QuestionPool will contain name of a group of questions of quiz and number of them asked in every attempt.
+type QuestionPool struct {
+	Name      string `json:"name"`
+	DrawCount int    `json:"drawCount"`
+}
+
+// validatePools will check that every pool has a unique name and enough questions to draw from,
+// and that every question belongs either to one of the pools or to none of them.
+func (q *Quiz) validatePools(v *validator) {
+	drawCounts := map[string]int{}
+	drawnPools := []int{} // indexes of valid pools which questions are drawn from
+	pools := v.field("pools")
+
+	for i := range q.Pools {
+		q.Pools[i].Name = strings.TrimSpace(q.Pools[i].Name)
+		pool := q.Pools[i]
+
+		if len(pool.Name) == 0 || len(pool.Name) > 30 {
+			pools.index(i).add("name", ViolationLength, "pool name must be between 1 and 30 characters")
+			continue
+		}
+
+		if _, ok := drawCounts[pool.Name]; ok {
+			pools.index(i).add("name", ViolationDuplicate, "pool "+pool.Name+" is specified more than once")
+			continue
+		}
+
+		if pool.DrawCount < 1 {
+			pools.index(i).add("drawCount", ViolationRange, "draw count of pool "+pool.Name+" should be atleast 1")
+		} else {
+			drawnPools = append(drawnPools, i)
+		}
+
+		drawCounts[pool.Name] = pool.DrawCount
+	}
+
+	totalQuestions := map[string]int{}
+	questions := v.field("questions")
+
+	for i := range q.Questions {
+		q.Questions[i].Pool = strings.TrimSpace(q.Questions[i].Pool)
+		pool := q.Questions[i].Pool
+
+		if pool == "" {
+			continue
+		}
+
+		if _, ok := drawCounts[pool]; !ok {
+			questions.index(i).add("pool", ViolationUnsupported, "pool "+pool+" of question is not specified in quiz")
+			continue
+		}
+
+		totalQuestions[pool]++
+	}
+
+	for _, i := range drawnPools {
+		if pool := q.Pools[i]; totalQuestions[pool.Name] < pool.DrawCount {
+			pools.index(i).add("drawCount", ViolationRange, "pool "+pool.Name+" does not have enough questions to draw from")
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidatePools will test that pools are named uniquely and have enough questions to draw from
+func TestValidatePools(t *testing.T) {
+	quiz := Quiz{
+		Pools:     []QuestionPool{{Name: " capitals ", DrawCount: 1}},
+		Questions: []Question{{Pool: "capitals "}, {}},
+	}
+
+	validatePools := func() error {
+		v := newValidator()
+		quiz.validatePools(v)
+		return v.err()
+	}
+
+	err := validatePools()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "capitals", quiz.Pools[0].Name)
+	assert.Equal(t, "capitals", quiz.Questions[0].Pool)
+
+	quiz.Pools[0].DrawCount = 2
+	err = validatePools()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "pool capitals does not have enough questions to draw from", err.Error())
+
+	quiz.Pools = append(quiz.Pools, QuestionPool{Name: "capitals", DrawCount: 1})
+	err = validatePools()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, []Violation{
+		{Field: "pools[1].name", Code: ViolationDuplicate, Message: "pool capitals is specified more than once"},
+		{Field: "pools[0].drawCount", Code: ViolationRange, Message: "pool capitals does not have enough questions to draw from"},
+	}, err.(*ValidationError).Violations)
+
+	quiz.Pools = nil
+	err = validatePools()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "pool capitals of question is not specified in quiz", err.Error())
+}
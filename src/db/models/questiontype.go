@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"sync"
+)
+
+// Types of questions supported by default.
+const (
+	QuestionTypeSingleChoice   = "singleChoice"
+	QuestionTypeMultipleChoice = "multipleChoice"
+	QuestionTypeTrueFalse      = "trueFalse"
+	QuestionTypeShortText      = "shortText"
+	QuestionTypeNumeric        = "numeric"
+)
+
+// QuestionType will validate questions of a type and grade answers submitted for them.
+// New types are added by implementing this interface and registering it using RegisterQuestionType.
+type QuestionType interface {
+	// Validate will check that options and answers of question are valid for this type.
+	Validate(question *Question) error
+
+	// Grade will return credit for response between 0 and 1, where 1 is a correct answer
+	// and a value in between is a partially correct answer.
+	// It returns an error if response does not contain a valid answer for this type.
+	Grade(question *Question, response *UserResponse) (float64, error)
+
+	// CorrectAnswer will return the correct answer of question which is shown after it is answered.
+	CorrectAnswer(question *Question) *CorrectAnswer
+}
+
+// CorrectAnswer will contain the correct answer of a question, only fields used by the question type are set.
+type CorrectAnswer struct {
+	Options         []Option `json:"options,omitempty"`
+	AcceptedAnswers []string `json:"acceptedAnswers,omitempty"`
+	NumericAnswer   *float64 `json:"numericAnswer,omitempty"`
+	Tolerance       float64  `json:"tolerance,omitempty"`
+}
+
+// FullCredit is the credit of a correct answer.
+const FullCredit = 1.0
+
+var (
+	questionTypesMu sync.RWMutex
+	questionTypes   = map[string]QuestionType{}
+)
+
+// RegisterQuestionType will make question type available under given name, registering same name again replaces it.
+func RegisterQuestionType(name string, questionType QuestionType) {
+	questionTypesMu.Lock()
+	defer questionTypesMu.Unlock()
+
+	questionTypes[name] = questionType
+}
+
+// GetQuestionType will return question type registered under given name.
+// Questions created before types were introduced do not have a type and are single choice.
+func GetQuestionType(name string) (QuestionType, error) {
+	if name == "" {
+		name = QuestionTypeSingleChoice
+	}
+
+	questionTypesMu.RLock()
+	defer questionTypesMu.RUnlock()
+
+	questionType, ok := questionTypes[name]
+	if !ok {
+		return nil, errors.New("question type " + name + " is not supported")
+	}
+
+	return questionType, nil
+}
+
+func init() {
+	RegisterQuestionType(QuestionTypeSingleChoice, singleChoice{})
+	RegisterQuestionType(QuestionTypeMultipleChoice, multipleChoice{})
+	RegisterQuestionType(QuestionTypeTrueFalse, trueFalse{})
+	RegisterQuestionType(QuestionTypeShortText, shortText{})
+	RegisterQuestionType(QuestionTypeNumeric, numeric{})
+}
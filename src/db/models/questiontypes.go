@@ -0,0 +1,279 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// singleChoice is a question with 2 to 10 options where user selects one option.
+type singleChoice struct{}
+
+// Validate will check that question has 2 to 10 valid options with atleast one correct option.
+func (singleChoice) Validate(question *Question) error {
+	return validateChoices(question, 2, 10, false)
+}
+
+// Grade will check if the selected option is correct.
+func (singleChoice) Grade(question *Question, response *UserResponse) (float64, error) {
+	return gradeSelectedOption(question, response)
+}
+
+// CorrectAnswer will return all correct options.
+func (singleChoice) CorrectAnswer(question *Question) *CorrectAnswer {
+	return &CorrectAnswer{Options: correctOptions(question)}
+}
+
+// multipleChoice is a question with 2 to 10 options where user selects all options that apply.
+type multipleChoice struct{}
+
+// Validate will check that question has 2 to 10 valid options with atleast one correct option.
+func (multipleChoice) Validate(question *Question) error {
+	return validateChoices(question, 2, 10, false)
+}
+
+// Grade will give credit for every correct option selected and take it back for every wrong option selected.
+// Full credit is given only if all correct options and none of the wrong options are selected.
+func (multipleChoice) Grade(question *Question, response *UserResponse) (float64, error) {
+	if len(response.SelectedOptionIDs) == 0 {
+		return 0, errors.New("selected option IDs are required")
+	}
+
+	selected := map[uuid.UUID]bool{}
+
+	for _, optionID := range response.SelectedOptionIDs {
+		_, err := findOption(question, optionID)
+		if err != nil {
+			return 0, err
+		}
+
+		selected[optionID] = true
+	}
+
+	totalCorrect, selectedCorrect, selectedWrong := 0, 0, 0
+
+	for _, option := range question.Options {
+		if isOptionCorrect(option) {
+			totalCorrect++
+		}
+
+		if !selected[option.ID] {
+			continue
+		}
+
+		if isOptionCorrect(option) {
+			selectedCorrect++
+		} else {
+			selectedWrong++
+		}
+	}
+
+	if selectedCorrect <= selectedWrong {
+		return 0, nil
+	}
+
+	return float64(selectedCorrect-selectedWrong) / float64(totalCorrect), nil
+}
+
+// CorrectAnswer will return all correct options.
+func (multipleChoice) CorrectAnswer(question *Question) *CorrectAnswer {
+	return &CorrectAnswer{Options: correctOptions(question)}
+}
+
+// trueFalse is a question with exactly 2 options where one of them is correct.
+type trueFalse struct{}
+
+// Validate will check that question has 2 valid options with exactly one correct option.
+func (trueFalse) Validate(question *Question) error {
+	return validateChoices(question, 2, 2, true)
+}
+
+// Grade will check if the selected option is correct.
+func (trueFalse) Grade(question *Question, response *UserResponse) (float64, error) {
+	return gradeSelectedOption(question, response)
+}
+
+// CorrectAnswer will return the correct option.
+func (trueFalse) CorrectAnswer(question *Question) *CorrectAnswer {
+	return &CorrectAnswer{Options: correctOptions(question)}
+}
+
+// shortText is a question answered with text which must match one of the accepted answers.
+// Answers are compared ignoring case and repeated whitespace.
+type shortText struct{}
+
+// Validate will check that question has no options and atleast one accepted answer.
+func (shortText) Validate(question *Question) error {
+	v := newValidator()
+
+	if len(question.Options) != 0 {
+		v.add("options", ViolationConflict, "short text question should not have options")
+	}
+
+	if len(question.AcceptedAnswers) == 0 {
+		v.add("acceptedAnswers", ViolationRequired, "atleast one accepted answer must be present")
+	}
+
+	answers := v.field("acceptedAnswers")
+	for i, answer := range question.AcceptedAnswers {
+		if len(normalizeText(answer)) == 0 {
+			answers.index(i).add("", ViolationRequired, "accepted answer must be specified")
+		} else if len(answer) > 200 {
+			answers.index(i).add("", ViolationLength, "accepted answer should not exceed 200 characters")
+		}
+	}
+
+	return v.err()
+}
+
+// Grade will check if text answer matches one of the accepted answers.
+func (shortText) Grade(question *Question, response *UserResponse) (float64, error) {
+	answer := normalizeText(response.TextAnswer)
+	if len(answer) == 0 {
+		return 0, errors.New("text answer is required")
+	}
+
+	for _, acceptedAnswer := range question.AcceptedAnswers {
+		if strings.EqualFold(answer, normalizeText(acceptedAnswer)) {
+			return FullCredit, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// CorrectAnswer will return all accepted answers.
+func (shortText) CorrectAnswer(question *Question) *CorrectAnswer {
+	return &CorrectAnswer{AcceptedAnswers: question.AcceptedAnswers}
+}
+
+// numeric is a question answered with a number which is correct if it is within tolerance of the numeric answer.
+type numeric struct{}
+
+// Validate will check that question has no options, a numeric answer and a tolerance which is not negative.
+func (numeric) Validate(question *Question) error {
+	v := newValidator()
+
+	if len(question.Options) != 0 {
+		v.add("options", ViolationConflict, "numeric question should not have options")
+	}
+
+	if question.NumericAnswer == nil {
+		v.add("numericAnswer", ViolationRequired, "numeric answer must be specified")
+	}
+
+	if question.Tolerance < 0 {
+		v.add("tolerance", ViolationRange, "tolerance should not be negative")
+	}
+
+	return v.err()
+}
+
+// Grade will check if numeric answer is within tolerance of the correct answer.
+func (numeric) Grade(question *Question, response *UserResponse) (float64, error) {
+	if response.NumericAnswer == nil {
+		return 0, errors.New("numeric answer is required")
+	}
+
+	if math.Abs(*response.NumericAnswer-*question.NumericAnswer) > question.Tolerance {
+		return 0, nil
+	}
+
+	return FullCredit, nil
+}
+
+// CorrectAnswer will return the numeric answer along with its tolerance.
+func (numeric) CorrectAnswer(question *Question) *CorrectAnswer {
+	return &CorrectAnswer{NumericAnswer: question.NumericAnswer, Tolerance: question.Tolerance}
+}
+
+// validateChoices will check that question has between min and max valid options and atleast one correct option.
+// If exactlyOneCorrect is true, only one of the options can be correct.
+func validateChoices(question *Question, min, max int, exactlyOneCorrect bool) error {
+	v := newValidator()
+
+	if len(question.Options) < min || len(question.Options) > max {
+		if min == max {
+			v.add("options", ViolationLength, fmt.Sprintf("question should have exactly %d options", min))
+		} else {
+			v.add("options", ViolationLength, fmt.Sprintf("question should have between %d and %d options", min, max))
+		}
+	}
+
+	totalCorrect := 0
+	options := v.field("options")
+
+	for i := range question.Options {
+		question.Options[i].validate(options.index(i))
+
+		if isOptionCorrect(question.Options[i]) {
+			totalCorrect++
+		}
+	}
+
+	// correct options are only checked once there are options to choose from.
+	if len(question.Options) > 0 && totalCorrect == 0 {
+		v.add("options", ViolationRequired, "atleast one correct option must be present")
+	}
+
+	if exactlyOneCorrect && totalCorrect > 1 {
+		v.add("options", ViolationInvalid, "question should have exactly one correct option")
+	}
+
+	return v.err()
+}
+
+// gradeSelectedOption will check if the single option selected in response is correct.
+func gradeSelectedOption(question *Question, response *UserResponse) (float64, error) {
+	if response.SelectedOptionID == uuid.Nil {
+		return 0, errors.New("selected option ID is required")
+	}
+
+	option, err := findOption(question, response.SelectedOptionID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isOptionCorrect(*option) {
+		return 0, nil
+	}
+
+	return FullCredit, nil
+}
+
+// findOption will fetch option of question by given optionID.
+func findOption(question *Question, optionID uuid.UUID) (*Option, error) {
+	for _, option := range question.Options {
+		if option.ID == optionID {
+			return &option, nil
+		}
+	}
+
+	return nil, errors.New("option not found")
+}
+
+// correctOptions will return all options of question which are correct.
+func correctOptions(question *Question) []Option {
+	var options []Option
+
+	for _, option := range question.Options {
+		if isOptionCorrect(option) {
+			options = append(options, option)
+		}
+	}
+
+	return options
+}
+
+// isOptionCorrect will return whether option is marked as correct.
+func isOptionCorrect(option Option) bool {
+	return option.IsCorrect != nil && *option.IsCorrect
+}
+
+// normalizeText will trim text and replace repeated whitespace with a single space.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
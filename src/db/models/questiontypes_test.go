@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateUnsupportedType will test for question type which is not registered
+func TestValidateUnsupportedType(t *testing.T) {
+	question := Question{
+		Text: "This is question text",
+		Type: "essay",
+	}
+
+	err := question.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "question type essay is not supported", err.Error())
+}
+
+// TestValidateTrueFalse will test that true/false question has exactly one correct option out of two
+func TestValidateTrueFalse(t *testing.T) {
+	trueValue := true
+
+	question := Question{
+		Text: "Paris is capital of France",
+		Type: QuestionTypeTrueFalse,
+		Options: []Option{
+			{Answer: "True", IsCorrect: &trueValue},
+			{Answer: "False", IsCorrect: &trueValue},
+		},
+	}
+
+	err := question.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "question should have exactly one correct option", err.Error())
+}
+
+// TestGradeMultipleChoice will test partial credit for selecting some of the correct options
+func TestGradeMultipleChoice(t *testing.T) {
+	trueValue := true
+	falseValue := false
+
+	question := Question{
+		Text: "Which of these are prime numbers",
+		Type: QuestionTypeMultipleChoice,
+		Options: []Option{
+			{ID: uuid.New(), Answer: "2", IsCorrect: &trueValue},
+			{ID: uuid.New(), Answer: "3", IsCorrect: &trueValue},
+			{ID: uuid.New(), Answer: "4", IsCorrect: &falseValue},
+		},
+	}
+
+	assert.Nil(t, question.Validate())
+
+	questionType, err := GetQuestionType(question.Type)
+	assert.Nil(t, err)
+
+	credit, err := questionType.Grade(&question, &UserResponse{
+		SelectedOptionIDs: []uuid.UUID{question.Options[0].ID, question.Options[1].ID},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, FullCredit, credit)
+
+	credit, err = questionType.Grade(&question, &UserResponse{
+		SelectedOptionIDs: []uuid.UUID{question.Options[0].ID},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, credit)
+
+	credit, err = questionType.Grade(&question, &UserResponse{
+		SelectedOptionIDs: []uuid.UUID{question.Options[0].ID, question.Options[2].ID},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, credit)
+
+	_, err = questionType.Grade(&question, &UserResponse{SelectedOptionIDs: []uuid.UUID{uuid.New()}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "option not found", err.Error())
+}
+
+// TestGradeShortText will test that text answer is compared ignoring case and whitespace
+func TestGradeShortText(t *testing.T) {
+	question := Question{
+		Text:            "What is the capital of France",
+		Type:            QuestionTypeShortText,
+		AcceptedAnswers: []string{"Paris", "Paree"},
+	}
+
+	assert.Nil(t, question.Validate())
+
+	questionType, err := GetQuestionType(question.Type)
+	assert.Nil(t, err)
+
+	credit, err := questionType.Grade(&question, &UserResponse{TextAnswer: "  paris "})
+	assert.Nil(t, err)
+	assert.Equal(t, FullCredit, credit)
+
+	credit, err = questionType.Grade(&question, &UserResponse{TextAnswer: "London"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, credit)
+
+	_, err = questionType.Grade(&question, &UserResponse{})
+	assert.NotNil(t, err)
+	assert.Equal(t, "text answer is required", err.Error())
+}
+
+// TestGradeNumeric will test that numeric answer is correct within tolerance
+func TestGradeNumeric(t *testing.T) {
+	answer := 3.14
+
+	question := Question{
+		Text:          "What is the value of pi",
+		Type:          QuestionTypeNumeric,
+		NumericAnswer: &answer,
+		Tolerance:     0.01,
+	}
+
+	assert.Nil(t, question.Validate())
+
+	questionType, err := GetQuestionType(question.Type)
+	assert.Nil(t, err)
+
+	closeAnswer := 3.1415
+	credit, err := questionType.Grade(&question, &UserResponse{NumericAnswer: &closeAnswer})
+	assert.Nil(t, err)
+	assert.Equal(t, FullCredit, credit)
+
+	wrongAnswer := 3.2
+	credit, err = questionType.Grade(&question, &UserResponse{NumericAnswer: &wrongAnswer})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, credit)
+
+	question.Tolerance = -1
+	err = question.Validate()
+	assert.NotNil(t, err)
+	assert.Equal(t, "tolerance should not be negative", err.Error())
+}
@@ -1,52 +1,283 @@
 package models
 
 import (
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/utils"
 )
 
+// Policies deciding which attempts of a user count towards their final result.
+const (
+	ScoringPolicyBest    = "best"    // attempt with highest percentage
+	ScoringPolicyLatest  = "latest"  // attempt which ended last
+	ScoringPolicyAverage = "average" // average percentage of all attempts
+)
+
+// Modes deciding when users are shown whether their answers are correct.
+const (
+	FeedbackImmediate    = "immediate"    // as soon as an answer is submitted
+	FeedbackAfterAttempt = "afterAttempt" // once the attempt has ended
+	FeedbackAfterClose   = "afterClose"   // once the quiz has closed for everyone
+	FeedbackNever        = "never"
+)
+
+// MaxQuizAttempts is maximum number of attempts that can be allowed for a quiz.
+const MaxQuizAttempts = 100
+
 // Quiz will contain details related to quiz
 type Quiz struct {
-	ID        uuid.UUID  `json:"id"`
-	Title     string     `json:"title"`
-	MaxTime   uint64     `json:"maxTime"` // this will store time in minutes. Default value is 2 minutes
-	Questions []Question `json:"questions"`
+	ID                uuid.UUID      `json:"id"`
+	Title             string         `json:"title"`
+	MaxTime           uint64         `json:"maxTime"`            // this will store time in minutes. Default value is 2 minutes
+	NegativeMarking   float64        `json:"negativeMarking"`    // ratio of points of a question deducted for a wrong answer
+	PassingPercentage float64        `json:"passingPercentage"`  // minimum percentage of max score needed to pass
+	MaxAttempts       uint32         `json:"maxAttempts"`        // number of times a user can attempt the quiz. Default value is 1
+	AttemptCooldown   uint64         `json:"attemptCooldown"`    // minutes a user must wait after an attempt ends before starting another one
+	ScoringPolicy     string         `json:"scoringPolicy"`      // which attempts count towards final result of a user. Default value is best
+	ShuffleQuestions  bool           `json:"shuffleQuestions"`   // show questions in a different order for every attempt
+	ShuffleOptions    bool           `json:"shuffleOptions"`     // show options of questions in a different order for every attempt
+	FeedbackMode      string         `json:"feedbackMode"`       // when correctness of answers is revealed to users. Default value is immediate
+	ClosesAt          *time.Time     `json:"closesAt,omitempty"` // quiz can not be attempted or answered after this time
+	CreatedBy         uuid.UUID      `json:"createdBy"`          // ID of the user who created the quiz, only they can modify it
+	CreatedAt         time.Time      `json:"createdAt"`
+	IsArchived        bool           `json:"isArchived"`
+	Version           uint32         `json:"version"`  // incremented whenever title, time or questions change
+	Revision          uint64         `json:"revision"` // incremented on every update, used to detect concurrent updates
+	Tags              []string       `json:"tags"`
+	Pools             []QuestionPool `json:"pools,omitempty"` // pools from which questions are drawn randomly for every attempt
+	Questions         []Question     `json:"questions"`
 }
 
-// Validate will validate if all fields of quiz are valid.
+// QuizPatch will contain fields of quiz that can be partially updated. Fields which are nil are not updated.
+type QuizPatch struct {
+	Title             *string    `json:"title"`
+	MaxTime           *uint64    `json:"maxTime"`
+	NegativeMarking   *float64   `json:"negativeMarking"`
+	PassingPercentage *float64   `json:"passingPercentage"`
+	MaxAttempts       *uint32    `json:"maxAttempts"`
+	AttemptCooldown   *uint64    `json:"attemptCooldown"`
+	ScoringPolicy     *string    `json:"scoringPolicy"`
+	ShuffleQuestions  *bool      `json:"shuffleQuestions"`
+	ShuffleOptions    *bool      `json:"shuffleOptions"`
+	FeedbackMode      *string    `json:"feedbackMode"`
+	ClosesAt          *time.Time `json:"closesAt"`
+	IsArchived        *bool      `json:"isArchived"`
+	Tags              *[]string  `json:"tags"`
+
+	// ClearClosesAt will remove the close time of quiz, since a null closesAt can not be told apart from an omitted one.
+	ClearClosesAt bool `json:"clearClosesAt"`
+}
+
+// MediaIDs will return IDs of every media attached to questions and options of quiz, each of them only once.
+func (q *Quiz) MediaIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+
+	add := func(mediaIDs []uuid.UUID) {
+		for _, id := range mediaIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	for _, question := range q.Questions {
+		add(question.MediaIDs)
+		for _, option := range question.Options {
+			add(option.MediaIDs)
+		}
+	}
+
+	return ids
+}
+
+// Validate will validate all fields of quiz, returning a ValidationError listing every invalid field.
 func (q *Quiz) Validate() error {
+	v := newValidator()
+	q.validate(v)
+	return v.err()
+}
+
+// validate will add violations of every invalid field of quiz to v.
+func (q *Quiz) validate(v *validator) {
 	q.Title = strings.TrimSpace(q.Title)
+	validateTitle(v.field("title"), q.Title)
+
+	q.Tags = validateTags(v.field("tags"), q.Tags)
+
+	if q.NegativeMarking < 0 || q.NegativeMarking > 1 {
+		v.add("negativeMarking", ViolationRange, "negative marking should be between 0 and 1")
+	}
+
+	if q.PassingPercentage < 0 || q.PassingPercentage > 100 {
+		v.add("passingPercentage", ViolationRange, "passing percentage should be between 0 and 100")
+	}
+
+	if q.MaxAttempts == 0 {
+		q.MaxAttempts = 1
+	}
+
+	if q.ScoringPolicy == "" {
+		q.ScoringPolicy = ScoringPolicyBest
+	}
+
+	validateMaxAttempts(v.field("maxAttempts"), q.MaxAttempts)
+	validateScoringPolicy(v.field("scoringPolicy"), q.ScoringPolicy)
+
+	if q.FeedbackMode == "" {
+		q.FeedbackMode = FeedbackImmediate
+	}
+
+	validateFeedback(v, q.FeedbackMode, q.ClosesAt)
+
+	if len(q.Questions) == 0 {
+		v.add("questions", ViolationRequired, "at least one question is required")
+	}
+
+	questions := v.field("questions")
+	for i, question := range q.Questions {
+		question.validate(questions.index(i))
+	}
+
+	q.validatePools(v)
+}
+
+// Validate will validate fields of quiz which are specified in the patch, returning a ValidationError listing
+// every invalid field.
+func (q *QuizPatch) Validate() error {
+	v := newValidator()
 
-	if len(q.Title) == 0 {
-		return errors.New("title must be specified")
+	if q.Title == nil && q.MaxTime == nil && q.NegativeMarking == nil && q.PassingPercentage == nil &&
+		q.MaxAttempts == nil && q.AttemptCooldown == nil && q.ScoringPolicy == nil &&
+		q.ShuffleQuestions == nil && q.ShuffleOptions == nil && q.FeedbackMode == nil && q.ClosesAt == nil &&
+		!q.ClearClosesAt && q.IsArchived == nil && q.Tags == nil {
+		v.add("", ViolationRequired, "at least one field must be specified")
+		return v.err()
 	}
 
-	if len(q.Title) < 5 || len(q.Title) > 50 {
-		return errors.New("title must be between 5 and 50 characters")
+	if q.Title != nil {
+		title := strings.TrimSpace(*q.Title)
+		q.Title = &title
+		validateTitle(v.field("title"), title)
 	}
 
-	isValid, err := utils.ValidateString(q.Title, `^[a-zA-Z0-9@$()!%*/?&\s]+$`)
+	if q.NegativeMarking != nil && (*q.NegativeMarking < 0 || *q.NegativeMarking > 1) {
+		v.add("negativeMarking", ViolationRange, "negative marking should be between 0 and 1")
+	}
+
+	if q.PassingPercentage != nil && (*q.PassingPercentage < 0 || *q.PassingPercentage > 100) {
+		v.add("passingPercentage", ViolationRange, "passing percentage should be between 0 and 100")
+	}
+
+	if q.MaxAttempts != nil {
+		validateMaxAttempts(v.field("maxAttempts"), *q.MaxAttempts)
+	}
+
+	if q.ScoringPolicy != nil {
+		validateScoringPolicy(v.field("scoringPolicy"), *q.ScoringPolicy)
+	}
+
+	if q.FeedbackMode != nil {
+		validateFeedbackMode(v.field("feedbackMode"), *q.FeedbackMode)
+	}
+
+	if q.ClosesAt != nil && q.ClearClosesAt {
+		v.add("clearClosesAt", ViolationConflict, "close time can not be both set and cleared")
+	}
+
+	if q.Tags != nil {
+		tags := validateTags(v.field("tags"), *q.Tags)
+		q.Tags = &tags
+	}
+
+	return v.err()
+}
+
+// validateTitle will check that title has valid length and characters.
+func validateTitle(v *validator, title string) {
+	if len(title) == 0 {
+		v.add("", ViolationRequired, "title must be specified")
+		return
+	}
+
+	if len(title) < 5 || len(title) > 50 {
+		v.add("", ViolationLength, "title must be between 5 and 50 characters")
+		return
+	}
+
+	isValid, err := utils.ValidateString(title, `^[a-zA-Z0-9@$()!%*/?&\s]+$`)
 	if err != nil {
-		return err
+		v.add("", ViolationInvalid, err.Error())
+		return
 	}
 
 	if !isValid {
-		return errors.New("title contains invalid characters")
+		v.add("", ViolationInvalid, "title contains invalid characters")
 	}
+}
 
-	if len(q.Questions) == 0 {
-		return errors.New("at least one question is required")
+// validateMaxAttempts will check that number of attempts allowed is within limits.
+func validateMaxAttempts(v *validator, maxAttempts uint32) {
+	if maxAttempts < 1 || maxAttempts > MaxQuizAttempts {
+		v.add("", ViolationRange, "max attempts should be between 1 and 100")
 	}
+}
 
-	for _, question := range q.Questions {
-		err := question.Validate()
-		if err != nil {
-			return err
+// validateScoringPolicy will check that scoring policy is supported.
+func validateScoringPolicy(v *validator, scoringPolicy string) {
+	if scoringPolicy != ScoringPolicyBest && scoringPolicy != ScoringPolicyLatest && scoringPolicy != ScoringPolicyAverage {
+		v.add("", ViolationUnsupported, "scoring policy must be one of best, latest or average")
+	}
+}
+
+// validateTags will trim tags, remove duplicates ignoring case and check that every tag has valid length.
+// Only valid tags are returned.
+func validateTags(v *validator, tags []string) []string {
+	var validTags []string
+	seen := map[string]bool{}
+
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+
+		if len(tag) == 0 || len(tag) > 30 {
+			v.index(i).add("", ViolationLength, "tag must be between 1 and 30 characters")
+			continue
+		}
+
+		if seen[strings.ToLower(tag)] {
+			continue
 		}
+
+		seen[strings.ToLower(tag)] = true
+		validTags = append(validTags, tag)
+	}
+
+	return validTags
+}
+
+// ValidateFeedback will check that feedback mode is supported and that quiz closes if answers are revealed after it closes.
+func ValidateFeedback(feedbackMode string, closesAt *time.Time) error {
+	v := newValidator()
+	validateFeedback(v, feedbackMode, closesAt)
+	return v.err()
+}
+
+// validateFeedback will add violations of feedback mode and closing time of quiz to v.
+func validateFeedback(v *validator, feedbackMode string, closesAt *time.Time) {
+	validateFeedbackMode(v.field("feedbackMode"), feedbackMode)
+
+	if feedbackMode == FeedbackAfterClose && closesAt == nil {
+		v.add("closesAt", ViolationRequired, "closes at must be specified when feedback is revealed after quiz closes")
 	}
+}
 
-	return nil
+// validateFeedbackMode will check that feedback mode is supported.
+func validateFeedbackMode(v *validator, feedbackMode string) {
+	if feedbackMode != FeedbackImmediate && feedbackMode != FeedbackAfterAttempt &&
+		feedbackMode != FeedbackAfterClose && feedbackMode != FeedbackNever {
+		v.add("", ViolationUnsupported, "feedback mode must be one of immediate, afterAttempt, afterClose or never")
+	}
 }
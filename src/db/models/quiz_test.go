@@ -75,3 +75,126 @@ func TestValidateTotalQuestions(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "at least one question is required", err.Error())
 }
+
+// TestValidateTags will test that tags are trimmed and duplicate tags are removed
+func TestValidateTags(t *testing.T) {
+	quiz := Quiz{
+		Title:     "Sample quiz",
+		MaxTime:   2,
+		Tags:      []string{" Geography ", "geography", "capitals"},
+		Questions: []Question{},
+	}
+
+	err := quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "at least one question is required", err.Error())
+	assert.Equal(t, []string{"Geography", "capitals"}, quiz.Tags)
+
+	quiz.Tags = []string{" "}
+	err = quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "tag must be between 1 and 30 characters", err.Error())
+}
+
+// TestValidateRetakePolicy will test that retake policy gets default values and is validated
+func TestValidateRetakePolicy(t *testing.T) {
+	quiz := Quiz{
+		Title:     "Sample quiz",
+		MaxTime:   2,
+		Questions: []Question{},
+	}
+
+	_ = quiz.Validate()
+
+	assert.Equal(t, uint32(1), quiz.MaxAttempts)
+	assert.Equal(t, ScoringPolicyBest, quiz.ScoringPolicy)
+
+	quiz.MaxAttempts = MaxQuizAttempts + 1
+	err := quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "max attempts should be between 1 and 100", err.Error())
+
+	quiz.MaxAttempts = 3
+	quiz.ScoringPolicy = "first"
+	err = quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "scoring policy must be one of best, latest or average", err.Error())
+}
+
+// TestValidateFeedbackMode will test that feedback mode gets default value and quiz revealing it after closing has to close.
+func TestValidateFeedbackMode(t *testing.T) {
+	quiz := Quiz{
+		Title:     "Sample quiz",
+		MaxTime:   2,
+		Questions: []Question{},
+	}
+
+	_ = quiz.Validate()
+
+	assert.Equal(t, FeedbackImmediate, quiz.FeedbackMode)
+
+	quiz.FeedbackMode = "later"
+	err := quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "feedback mode must be one of immediate, afterAttempt, afterClose or never", err.Error())
+
+	quiz.FeedbackMode = FeedbackAfterClose
+	err = quiz.Validate()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "closes at must be specified when feedback is revealed after quiz closes", err.Error())
+}
+
+// TestValidateListsAllViolations will test that every invalid field of quiz, its questions and options is reported with its path.
+func TestValidateListsAllViolations(t *testing.T) {
+	trueValue := true
+	answer := 5.0
+
+	quiz := Quiz{
+		Title:           "Quiz",
+		NegativeMarking: 2,
+		Tags:            []string{"maths", ""},
+		Questions: []Question{
+			{
+				Text: "Valid question",
+				Options: []Option{
+					{Answer: "Answer 1", IsCorrect: &trueValue},
+					{Answer: "Answer 2", IsCorrect: &trueValue},
+				},
+			},
+			{
+				Text: " ",
+				Type: QuestionTypeTrueFalse,
+				Options: []Option{
+					{Answer: "True", IsCorrect: &trueValue},
+					{Answer: ""},
+				},
+			},
+			{Text: "Numeric question", Type: QuestionTypeNumeric, NumericAnswer: &answer, Tolerance: -1, Pool: "hard"},
+		},
+	}
+
+	err := quiz.Validate()
+
+	validationErr, ok := err.(*ValidationError)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, "title must be between 5 and 50 characters", err.Error())
+	assert.Equal(t, []Violation{
+		{Field: "title", Code: ViolationLength, Message: "title must be between 5 and 50 characters"},
+		{Field: "tags[1]", Code: ViolationLength, Message: "tag must be between 1 and 30 characters"},
+		{Field: "negativeMarking", Code: ViolationRange, Message: "negative marking should be between 0 and 1"},
+		{Field: "questions[1].text", Code: ViolationRequired, Message: "text must be specified"},
+		{Field: "questions[1].options[1].answer", Code: ViolationRequired, Message: "answer must be specified"},
+		{Field: "questions[1].options[1].isCorrect", Code: ViolationRequired, Message: "whether answer is correct or not must be specified"},
+		{Field: "questions[2].tolerance", Code: ViolationRange, Message: "tolerance should not be negative"},
+		{Field: "questions[2].pool", Code: ViolationUnsupported, Message: "pool hard of question is not specified in quiz"},
+	}, validationErr.Violations)
+}
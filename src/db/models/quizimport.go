@@ -0,0 +1,16 @@
+package models
+
+// ImportError will contain a problem found in a row or question of an imported file.
+type ImportError struct {
+	Line  int    `json:"line,omitempty"`  // line of the file where the row or question starts, 0 for problems of a whole quiz
+	Quiz  string `json:"quiz,omitempty"`  // title of the quiz which the row or question belongs to
+	Field string `json:"field,omitempty"` // JSON path of the invalid field of quiz, empty if the file could not be read
+	Error string `json:"error"`
+}
+
+// ImportReport will contain quizzes created from an imported file. If any row or question of the file is invalid
+// no quiz is created, and every problem found in the file is reported instead.
+type ImportReport struct {
+	Quizzes []QuizSummary `json:"quizzes"`
+	Errors  []ImportError `json:"errors"`
+}
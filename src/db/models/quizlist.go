@@ -0,0 +1,130 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fields by which quizzes can be sorted.
+const (
+	SortByTitle         = "title"
+	SortByCreatedAt     = "createdAt"
+	SortByQuestionCount = "questionCount"
+)
+
+// Order in which quizzes can be sorted.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+const (
+	// DefaultQuizListLimit is number of quizzes returned in a page when limit is not specified.
+	DefaultQuizListLimit = 20
+
+	// MaxQuizListLimit is maximum number of quizzes that can be returned in a page.
+	MaxQuizListLimit = 100
+)
+
+// QuizQuery will contain filters, sorting and pagination used to list quizzes. Filters which are empty are not applied.
+type QuizQuery struct {
+	Search     string     // case-insensitive search in title
+	CreatedBy  *uuid.UUID // only quizzes created by this user
+	Tag        string     // only quizzes having this tag, ignoring case
+	IsArchived *bool
+
+	// AttemptedBy along with HasAttempted will list only quizzes which were (or were not) attempted by the user.
+	AttemptedBy  *uuid.UUID
+	HasAttempted *bool
+
+	SortBy string
+	Order  string
+
+	// After is position of the last quiz of previous page, quizzes sorted before or at it are skipped.
+	After *QuizPosition
+	Limit int
+}
+
+// QuizPosition is position of a quiz in a sorted list, the value of the field quizzes are sorted by along with ID
+// of the quiz. Only the field quizzes are sorted by is set, so the position does not change with other fields.
+type QuizPosition struct {
+	Title         string    `json:"title,omitempty"`     // in lower case, same as titles are compared
+	CreatedAt     int64     `json:"createdAt,omitempty"` // in milliseconds
+	QuestionCount int       `json:"questionCount,omitempty"`
+	ID            uuid.UUID `json:"id"`
+}
+
+// QuizSummary will contain details of quiz shown when listing quizzes.
+type QuizSummary struct {
+	ID            uuid.UUID `json:"id"`
+	Title         string    `json:"title"`
+	MaxTime       uint64    `json:"maxTime"`
+	CreatedBy     uuid.UUID `json:"createdBy"`
+	CreatedAt     time.Time `json:"createdAt"`
+	IsArchived    bool      `json:"isArchived"`
+	Version       uint32    `json:"version"`
+	Tags          []string  `json:"tags"`
+	QuestionCount int       `json:"questionCount"`
+}
+
+// QuizList will contain a page of quizzes along with cursor to fetch the next page.
+type QuizList struct {
+	Quizzes    []QuizSummary `json:"quizzes"`
+	NextCursor string        `json:"nextCursor,omitempty"` // empty when there are no more quizzes
+	Total      int           `json:"total"`                // number of quizzes matching the filters across all pages
+}
+
+// PositionOf will return position of quiz in a list sorted by field sortBy.
+func PositionOf(quiz *Quiz, sortBy string) QuizPosition {
+	position := QuizPosition{ID: quiz.ID}
+
+	switch sortBy {
+	case SortByTitle:
+		position.Title = strings.ToLower(quiz.Title)
+	case SortByCreatedAt:
+		position.CreatedAt = quiz.CreatedAt.UnixMilli()
+	case SortByQuestionCount:
+		position.QuestionCount = len(quiz.Questions)
+	}
+
+	return position
+}
+
+// Validate will validate sorting and pagination of the query and set their default values.
+func (q *QuizQuery) Validate() error {
+	if q.SortBy == "" {
+		q.SortBy = SortByCreatedAt
+	}
+
+	if q.SortBy != SortByTitle && q.SortBy != SortByCreatedAt && q.SortBy != SortByQuestionCount {
+		return errors.New("sort must be one of title, createdAt or questionCount")
+	}
+
+	if q.Order == "" {
+		q.Order = OrderAsc
+		if q.SortBy == SortByCreatedAt {
+			q.Order = OrderDesc
+		}
+	}
+
+	if q.Order != OrderAsc && q.Order != OrderDesc {
+		return errors.New("order must be either asc or desc")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultQuizListLimit
+	}
+
+	if q.Limit < 0 || q.Limit > MaxQuizListLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+
+	if q.HasAttempted != nil && q.AttemptedBy == nil {
+		return errors.New("user must be specified to filter attempted quizzes")
+	}
+
+	return nil
+}
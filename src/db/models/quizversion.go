@@ -0,0 +1,29 @@
+package models
+
+import "github.com/google/uuid"
+
+// Types of change made to a quiz between two versions.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// QuizDiff will contain all changes made to a quiz between two versions.
+type QuizDiff struct {
+	QuizID      uuid.UUID    `json:"quizID"`
+	FromVersion uint32       `json:"fromVersion"`
+	ToVersion   uint32       `json:"toVersion"`
+	Changes     []QuizChange `json:"changes"`
+}
+
+// QuizChange will contain a single change made to quiz, question or option.
+// Field is the name of the changed field, e.g. "title", "question", "question.text" or "option.isCorrect".
+type QuizChange struct {
+	Type       string      `json:"type"`
+	Field      string      `json:"field"`
+	QuestionID *uuid.UUID  `json:"questionID,omitempty"`
+	OptionID   *uuid.UUID  `json:"optionID,omitempty"`
+	From       interface{} `json:"from,omitempty"`
+	To         interface{} `json:"to,omitempty"`
+}
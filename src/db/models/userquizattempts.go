@@ -7,15 +7,68 @@ import (
 	"github.com/google/uuid"
 )
 
+// Outcomes of an attempt which has ended.
+const (
+	OutcomePassed    = "passed"
+	OutcomeFailed    = "failed"
+	OutcomeTimedOut  = "timedOut"  // maximum time of quiz exceeded before all questions were answered
+	OutcomeAbandoned = "abandoned" // maximum time of quiz exceeded without answering any question
+)
+
 // UserQuizAttempts will contain details about a user and quiz they have given.
 type UserQuizAttempts struct {
-	ID            uuid.UUID      `json:"id"`
-	UserID        uuid.UUID      `json:"userID"`
-	QuizID        uuid.UUID      `json:"quizID"`
-	StartedAt     *time.Time     `json:"startedAt"`
-	EndedAt       *time.Time     `json:"endAt"`
-	TotalScore    uint32         `json:"totalScore"`
-	UserResponses []UserResponse `json:"userResponses"`
+	ID                uuid.UUID      `json:"id"`
+	UserID            uuid.UUID      `json:"userID"`
+	QuizID            uuid.UUID      `json:"quizID"`
+	QuizVersion       uint32         `json:"quizVersion"`           // version of quiz the attempt was started on
+	AttemptNumber     uint32         `json:"attemptNumber"`         // starts from 1 for the first attempt of quiz made by the user
+	QuestionIDs       []uuid.UUID    `json:"questionIDs,omitempty"` // questions drawn for the attempt, all questions of quiz if empty
+	StartedAt         *time.Time     `json:"startedAt"`
+	EndedAt           *time.Time     `json:"endAt"`
+	ExpiresAt         *time.Time     `json:"expiresAt"`         // attempt is ended as timed out if it has not ended by this time
+	TotalScore        float64        `json:"totalScore"`        // sum of scores of all responses, negative marking can make it negative
+	MaxScore          float64        `json:"maxScore"`          // sum of points of all questions
+	Percentage        float64        `json:"percentage"`        // total score as percentage of max score, rounded to 2 decimals
+	Outcome           string         `json:"outcome,omitempty"` // set once attempt has ended
+	PassingPercentage float64        `json:"passingPercentage"` // passing percentage of quiz the outcome was judged against
+	UserResponses     []UserResponse `json:"userResponses"`
+	Version           uint64         `json:"version"` // incremented on every update, used to detect concurrent updates
+}
+
+// UserQuizResult will contain attempt of a user along with the version of quiz the attempt was made on.
+type UserQuizResult struct {
+	UserQuizAttempts
+	Quiz Quiz `json:"quiz"`
+}
+
+// CurrentAttempt will contain attempt which is in progress along with details needed to continue it on another device.
+type CurrentAttempt struct {
+	UserQuizAttempts
+	RemainingSeconds    int64       `json:"remainingSeconds"` // time left before attempt expires
+	TotalQuestions      int         `json:"totalQuestions"`
+	AnsweredQuestionIDs []uuid.UUID `json:"answeredQuestionIDs"`
+	NextQuestion        *Question   `json:"nextQuestion,omitempty"` // first question not answered yet in order of the attempt, without its answers
+}
+
+// UserQuizAttemptList will contain all attempts of a quiz made by a user along with their final result,
+// which is decided by the scoring policy of quiz using only attempts which have ended.
+type UserQuizAttemptList struct {
+	Attempts          []UserQuizAttempts `json:"attempts"`
+	ScoringPolicy     string             `json:"scoringPolicy"`
+	CountedAttemptID  *uuid.UUID         `json:"countedAttemptID,omitempty"` // attempt which counts for best and latest policies
+	Percentage        float64            `json:"percentage"`
+	Outcome           string             `json:"outcome,omitempty"` // passed or failed, set once an attempt has ended
+	AttemptsRemaining uint32             `json:"attemptsRemaining"`
+	NextAttemptAt     *time.Time         `json:"nextAttemptAt,omitempty"` // set when user has to wait before starting next attempt
+}
+
+// Number will return number of attempt, attempts made before retakes were introduced are the first attempt.
+func (u *UserQuizAttempts) Number() uint32 {
+	if u.AttemptNumber == 0 {
+		return 1
+	}
+
+	return u.AttemptNumber
 }
 
 // Validate will check if valid userID and quizID are provided.
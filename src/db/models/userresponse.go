@@ -8,13 +8,21 @@ import (
 
 // UserResponse is the structure for user response for specific quiz
 type UserResponse struct {
-	ID                uuid.UUID `json:"id"`
-	UserID            uuid.UUID `json:"userID"`
-	QuizID            uuid.UUID `json:"quizID"`
-	UserQuizAttemptID uuid.UUID `json:"userQuizAttemptID"`
-	QuestionID        uuid.UUID `json:"questionID"`
-	SelectedOptionID  uuid.UUID `json:"selectedOptionID"`
-	IsCorrect         bool      `json:"isCorrect"`
+	ID                uuid.UUID  `json:"id"`
+	UserID            uuid.UUID  `json:"userID"`
+	QuizID            uuid.UUID  `json:"quizID"`
+	UserQuizAttemptID uuid.UUID  `json:"userQuizAttemptID"`
+	QuestionID        uuid.UUID  `json:"questionID"`
+	IsCorrect         *bool      `json:"isCorrect,omitempty"`      // hidden from the user until feedback mode of quiz reveals it
+	Score             *float64   `json:"score,omitempty"`          // points awarded for the answer, negative if a penalty was applied
+	IsSkipped         bool       `json:"isSkipped,omitempty"`      // question was not answered before attempt was finished
+	BankQuestionID    *uuid.UUID `json:"bankQuestionID,omitempty"` // question bank question which the question was copied from
+
+	// Answer of the user, only fields used by the type of question are set.
+	SelectedOptionID  uuid.UUID   `json:"selectedOptionID"`
+	SelectedOptionIDs []uuid.UUID `json:"selectedOptionIDs,omitempty"`
+	TextAnswer        string      `json:"textAnswer,omitempty"`
+	NumericAnswer     *float64    `json:"numericAnswer,omitempty"`
 }
 
 // Validate will validate if all fields for a user response are correctly specified.
@@ -31,8 +39,23 @@ func (u *UserResponse) Validate() error {
 		return errors.New("question ID is required")
 	}
 
-	if u.SelectedOptionID == uuid.Nil {
-		return errors.New("selected option ID is required")
+	if u.SelectedOptionID == uuid.Nil && len(u.SelectedOptionIDs) == 0 && len(u.TextAnswer) == 0 && u.NumericAnswer == nil {
+		return errors.New("answer is required")
 	}
 	return nil
 }
+
+// AnswerResult will contain grading of an answer submitted by user along with score of the attempt so far.
+// Grading and score are left out until feedback mode of quiz reveals them.
+type AnswerResult struct {
+	FeedbackMode    string         `json:"feedbackMode"`
+	IsCorrect       *bool          `json:"isCorrect,omitempty"`
+	Score           *float64       `json:"score,omitempty"`
+	CorrectAnswer   *CorrectAnswer `json:"correctAnswer,omitempty"`
+	CorrectOption   *Option        `json:"correctOption,omitempty"` // kept for clients which only understand single choice questions
+	Explanation     string         `json:"explanation,omitempty"`
+	SelectedOptions []Option       `json:"selectedOptions,omitempty"` // options selected by user along with their feedback
+	TotalScore      *float64       `json:"totalScore,omitempty"`
+	MaxScore        float64        `json:"maxScore"`
+	Percentage      *float64       `json:"percentage,omitempty"`
+}
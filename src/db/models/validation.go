@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Codes of violations found while validating, clients can rely on them to not change.
+const (
+	ViolationRequired    = "required"    // field must be specified
+	ViolationLength      = "length"      // text or list is too short or too long
+	ViolationRange       = "range"       // number is out of its allowed range
+	ViolationInvalid     = "invalid"     // value contains invalid characters or is malformed
+	ViolationUnsupported = "unsupported" // value is not one of the allowed values
+	ViolationDuplicate   = "duplicate"   // value is specified more than once
+	ViolationConflict    = "conflict"    // value conflicts with another field
+)
+
+// Violation will contain a single problem found while validating a request.
+type Violation struct {
+	Field   string `json:"field"` // JSON path of the field e.g. questions[3].options[1].answer, empty for the whole request
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError will contain every violation found while validating a request, in the order they were found.
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+// Error will return message of the first violation, all of them are listed in Violations.
+func (e *ValidationError) Error() string {
+	return e.Violations[0].Message
+}
+
+// validator will collect violations of fields under path, nested validators share the violations.
+type validator struct {
+	path       string
+	violations *[]Violation
+}
+
+func newValidator() *validator {
+	return &validator{violations: &[]Violation{}}
+}
+
+// add will add violation of field, which is relative to path of validator.
+func (v *validator) add(field, code, message string) {
+	*v.violations = append(*v.violations, Violation{
+		Field:   joinFieldPath(v.path, field),
+		Code:    code,
+		Message: message,
+	})
+}
+
+// merge will add violations of err returned by validating field. Errors which are not validation errors,
+// such as those of question types registered by other packages, are added as invalid field.
+func (v *validator) merge(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		v.add(field, ViolationInvalid, err.Error())
+		return
+	}
+
+	for _, violation := range validationErr.Violations {
+		v.add(joinFieldPath(field, violation.Field), violation.Code, violation.Message)
+	}
+}
+
+// field will return validator of field nested under path of v.
+func (v *validator) field(name string) *validator {
+	return &validator{path: joinFieldPath(v.path, name), violations: v.violations}
+}
+
+// index will return validator of element at index of list under path of v.
+func (v *validator) index(i int) *validator {
+	return &validator{path: v.path + "[" + strconv.Itoa(i) + "]", violations: v.violations}
+}
+
+// err will return a ValidationError containing all violations, or nil if there are none.
+func (v *validator) err() error {
+	if len(*v.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: *v.violations}
+}
+
+// joinFieldPath will append field to path, either of them can be empty.
+func joinFieldPath(path, field string) string {
+	switch {
+	case path == "":
+		return field
+	case field == "":
+		return path
+	case strings.HasPrefix(field, "["):
+		return path + field
+	default:
+		return path + "." + field
+	}
+}
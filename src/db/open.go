@@ -0,0 +1,37 @@
+package db
+
+import "fmt"
+
+const (
+	// DriverMemory keeps all records in memory and loses them on restart.
+	DriverMemory = "memory"
+
+	// DriverSQLite stores all records in an embedded sqlite database.
+	DriverSQLite = "sqlite"
+)
+
+// Open will create repository for given driver. For sqlite dsn is the database file, and for memory
+// it is the directory used for write-ahead log and snapshots. Memory records are not persisted if dsn is empty.
+//
+// Like OpenDatabase, repository is returned along with *CorruptLogError if tail of the log was discarded.
+func Open(driver, dsn string) (Repository, error) {
+	switch driver {
+	case "", DriverMemory:
+		if dsn == "" {
+			return NewDatabase(), nil
+		}
+
+		db, err := OpenDatabase(dsn, DefaultSnapshotEvery)
+		if db == nil {
+			return nil, err
+		}
+		return db, err
+	case DriverSQLite:
+		if dsn == "" {
+			dsn = "quiz.db"
+		}
+		return NewSQLiteDatabase(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %s", driver)
+	}
+}
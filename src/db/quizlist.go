@@ -0,0 +1,101 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// quizSortColumns contains the sqlite expression used to sort quizzes by every supported field.
+// Creation time is compared in milliseconds, same as compareQuizzes.
+var quizSortColumns = map[string]string{
+	models.SortByTitle:         `title_key`,
+	models.SortByCreatedAt:     `CAST(round(unixepoch(json_extract(data, '$.createdAt'), 'subsec') * 1000) AS INTEGER)`,
+	models.SortByQuestionCount: `COALESCE(json_array_length(data, '$.questions'), 0)`,
+}
+
+// quizSortKey will return value of position compared with the sqlite expression of quizSortColumns[sortBy].
+func quizSortKey(position *models.QuizPosition, sortBy string) interface{} {
+	switch sortBy {
+	case models.SortByTitle:
+		return position.Title
+	case models.SortByQuestionCount:
+		return position.QuestionCount
+	}
+
+	return position.CreatedAt
+}
+
+// matchesQuizQuery will check if quiz satisfies all filters of the query.
+// hasAttempted is called only when query filters quizzes attempted by a user.
+func matchesQuizQuery(quiz *models.Quiz, query *models.QuizQuery, hasAttempted func() bool) bool {
+	if query.Search != "" && !strings.Contains(strings.ToLower(quiz.Title), strings.ToLower(query.Search)) {
+		return false
+	}
+
+	if query.CreatedBy != nil && quiz.CreatedBy != *query.CreatedBy {
+		return false
+	}
+
+	if query.IsArchived != nil && quiz.IsArchived != *query.IsArchived {
+		return false
+	}
+
+	if query.Tag != "" && !hasTag(quiz.Tags, query.Tag) {
+		return false
+	}
+
+	if query.HasAttempted != nil && hasAttempted() != *query.HasAttempted {
+		return false
+	}
+
+	return true
+}
+
+// hasTag will check if tags of a quiz or bank question contain given tag, ignoring case.
+func hasTag(tags []string, tag string) bool {
+	for _, recordTag := range tags {
+		if strings.EqualFold(recordTag, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compareQuizzes will compare positions of quizzes by the field and order of query, quizzes with equal field are
+// ordered by their ID. It returns a negative number if a is listed before b.
+func compareQuizzes(a, b models.QuizPosition, query *models.QuizQuery) int {
+	result := 0
+
+	switch query.SortBy {
+	case models.SortByTitle:
+		result = strings.Compare(a.Title, b.Title)
+	case models.SortByCreatedAt:
+		result = compareInts(a.CreatedAt, b.CreatedAt)
+	case models.SortByQuestionCount:
+		result = compareInts(int64(a.QuestionCount), int64(b.QuestionCount))
+	}
+
+	if result == 0 {
+		result = strings.Compare(a.ID.String(), b.ID.String())
+	}
+
+	if query.Order == models.OrderDesc {
+		return -result
+	}
+
+	return result
+}
+
+// compareInts will return -1, 0 or 1 if a is less than, equal to or greater than b.
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+var (
+	// ErrRecordNotFound is returned by a repository when the requested record does not exist.
+	ErrRecordNotFound = errors.New("record not found")
+
+	// ErrDuplicateRecord is returned by a repository when a record with the same unique key already exists.
+	ErrDuplicateRecord = errors.New("duplicate record")
+
+	// ErrVersionConflict is returned by a repository when the record was modified after it was read.
+	ErrVersionConflict = errors.New("record was modified concurrently")
+
+	// ErrRecordInUse is returned by a repository when a record can not be deleted because other records refer to it.
+	ErrRecordInUse = errors.New("record is in use")
+)
+
+// QuizRepository will consist of methods to store and fetch quizzes.
+// CreateQuiz and UpdateQuiz return ErrDuplicateRecord if another quiz with same title exists.
+// Both of them also store an immutable snapshot of quiz.Version unless it is already stored.
+// UpdateQuiz is a compare-and-swap on the quiz revision: it returns ErrVersionConflict if the stored revision
+// differs from quiz.Revision or quiz.Version is neither the stored version nor the next one, otherwise it increments quiz.Revision.
+// DeleteQuiz returns ErrRecordInUse if the quiz has been attempted, which is checked atomically with deleting it.
+// ListQuizzes returns at most query.Limit quizzes sorted after the position query.After along with the total number
+// of quizzes matching the filters. The quiz at query.After does not need to exist anymore.
+// ListQuizVersionsByMedia returns every stored version of quizzes which attaches the media to a question or option.
+type QuizRepository interface {
+	CreateQuiz(quiz *models.Quiz) error
+	GetQuizByID(quizID uuid.UUID) (*models.Quiz, error)
+	GetQuizByTitle(title string) (*models.Quiz, error)
+	UpdateQuiz(quiz *models.Quiz) error
+	DeleteQuiz(quizID uuid.UUID) error
+	ListQuizzes(query *models.QuizQuery) ([]models.Quiz, int, error)
+	GetQuizVersion(quizID uuid.UUID, version uint32) (*models.Quiz, error)
+	ListQuizVersions(quizID uuid.UUID) ([]models.Quiz, error)
+	ListQuizVersionsByMedia(mediaID uuid.UUID) ([]models.Quiz, error)
+}
+
+// QuestionBankRepository will consist of methods to store and fetch questions of question bank.
+// ListBankQuestions returns questions created by given user sorted by creation time,
+// only questions having given tag ignoring case are returned unless tag is empty.
+type QuestionBankRepository interface {
+	CreateBankQuestion(question *models.BankQuestion) error
+	GetBankQuestionByID(questionID uuid.UUID) (*models.BankQuestion, error)
+	ListBankQuestions(createdBy uuid.UUID, tag string) ([]models.BankQuestion, error)
+	UpdateBankQuestion(question *models.BankQuestion) error
+	DeleteBankQuestion(questionID uuid.UUID) error
+}
+
+// MediaRepository will consist of methods to store and fetch details of uploaded media.
+// Content of media is kept in file storage, only its details are stored in the repository.
+type MediaRepository interface {
+	CreateMedia(media *models.Media) error
+	GetMediaByID(mediaID uuid.UUID) (*models.Media, error)
+}
+
+// UserRepository will consist of methods to store and fetch users.
+// CreateUser returns ErrDuplicateRecord if a user with same username exists.
+type UserRepository interface {
+	CreateUser(user *models.User) error
+	GetUserByID(userID uuid.UUID) (*models.User, error)
+	GetUserByUsername(username string) (*models.User, error)
+}
+
+// AttemptRepository will consist of methods to store and fetch user quiz attempts.
+// CreateAttempt returns ErrDuplicateRecord if the user has already made an attempt of the quiz with same attempt number,
+// and ErrRecordNotFound if the quiz does not exist anymore.
+// GetAttemptByUserAndQuiz returns the latest attempt, ListAttemptsByUserAndQuiz returns all of them sorted by attempt number.
+// UpdateAttempt is a compare-and-swap on the attempt version: it returns ErrVersionConflict
+// if the stored version differs from attempt.Version, otherwise it increments attempt.Version.
+// ListResponsesByBankQuestion returns responses of all attempts submitted for questions copied from given bank question.
+// ListExpiredAttempts returns attempts which have not ended and expire at or before given time,
+// along with attempts which have not ended and were started before expiry time was stored.
+type AttemptRepository interface {
+	CreateAttempt(attempt *models.UserQuizAttempts) error
+	GetAttemptByID(attemptID uuid.UUID) (*models.UserQuizAttempts, error)
+	GetAttemptByUserAndQuiz(userID, quizID uuid.UUID) (*models.UserQuizAttempts, error)
+	ListAttemptsByUserAndQuiz(userID, quizID uuid.UUID) ([]models.UserQuizAttempts, error)
+	CountAttemptsByQuiz(quizID uuid.UUID) (int, error)
+	UpdateAttempt(attempt *models.UserQuizAttempts) error
+	ListExpiredAttempts(before time.Time) ([]models.UserQuizAttempts, error)
+	ListResponsesByBankQuestion(bankQuestionID uuid.UUID) ([]models.UserResponse, error)
+}
+
+// Repository is implemented by every storage backend used by the services.
+type Repository interface {
+	QuizRepository
+	QuestionBankRepository
+	MediaRepository
+	UserRepository
+	AttemptRepository
+	Close() error
+}
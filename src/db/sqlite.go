@@ -0,0 +1,810 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// schema contains statements to create all tables used by SQLDatabase.
+// Records are stored as JSON documents along with the columns used for lookups.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS quizzes (
+		id TEXT PRIMARY KEY,
+		title_key TEXT NOT NULL UNIQUE,
+		data TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS quiz_versions (
+		quiz_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (quiz_id, version)
+	)`,
+	`CREATE TABLE IF NOT EXISTS bank_questions (
+		id TEXT PRIMARY KEY,
+		created_by TEXT NOT NULL,
+		data TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_bank_questions_created_by ON bank_questions (created_by)`,
+	`CREATE TABLE IF NOT EXISTS media (
+		id TEXT PRIMARY KEY,
+		data TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS users (
+		id TEXT PRIMARY KEY,
+		username_key TEXT NOT NULL UNIQUE,
+		data TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS user_quiz_attempts (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		quiz_id TEXT NOT NULL,
+		version INTEGER NOT NULL DEFAULT 0,
+		data TEXT NOT NULL
+	)`,
+	// users could attempt a quiz only once before retakes were introduced.
+	`DROP INDEX IF EXISTS idx_user_quiz_attempts_user_quiz`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_quiz_attempts_user_quiz_number ON user_quiz_attempts
+		(user_id, quiz_id, COALESCE(json_extract(data, '$.attemptNumber'), 1))`,
+	`CREATE INDEX IF NOT EXISTS idx_user_quiz_attempts_quiz ON user_quiz_attempts (quiz_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_user_quiz_attempts_unfinished ON user_quiz_attempts (id)
+		WHERE json_extract(data, '$.endAt') IS NULL`,
+	// attempts having responses for questions copied from every bank question, used for statistics of bank questions.
+	`CREATE TABLE IF NOT EXISTS attempt_bank_questions (
+		bank_question_id TEXT NOT NULL,
+		attempt_id TEXT NOT NULL,
+		PRIMARY KEY (bank_question_id, attempt_id)
+	)`,
+	// attempts stored before the table was introduced are indexed as well, already indexed attempts are ignored.
+	`INSERT OR IGNORE INTO attempt_bank_questions (bank_question_id, attempt_id)
+		SELECT DISTINCT json_extract(response.value, '$.bankQuestionID'), user_quiz_attempts.id
+		FROM user_quiz_attempts, json_each(user_quiz_attempts.data, '$.userResponses') AS response
+		WHERE json_extract(response.value, '$.bankQuestionID') IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM attempt_bank_questions WHERE attempt_id = user_quiz_attempts.id)`,
+	// versions of quizzes attaching every media, used to check who can download the media.
+	`CREATE TABLE IF NOT EXISTS quiz_version_media (
+		media_id TEXT NOT NULL,
+		quiz_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		PRIMARY KEY (media_id, quiz_id, version)
+	)`,
+	// versions stored before the table was introduced are indexed as well.
+	`INSERT OR IGNORE INTO quiz_version_media (media_id, quiz_id, version)
+		SELECT media.value, quiz_versions.quiz_id, quiz_versions.version
+		FROM quiz_versions, json_each(quiz_versions.data, '$.questions') AS question,
+			json_each(question.value, '$.mediaIDs') AS media
+		UNION
+		SELECT media.value, quiz_versions.quiz_id, quiz_versions.version
+		FROM quiz_versions, json_each(quiz_versions.data, '$.questions') AS question,
+			json_each(question.value, '$.options') AS opt, json_each(opt.value, '$.mediaIDs') AS media`,
+}
+
+// SQLDatabase will store all records in an embedded sqlite database.
+type SQLDatabase struct {
+	conn *sql.DB
+}
+
+// NewSQLiteDatabase will open sqlite database at given dsn, create tables and seed dummy data.
+// Use ":memory:" as dsn for a database which is discarded on close.
+func NewSQLiteDatabase(dsn string) (*SQLDatabase, error) {
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite allows single writer, and every connection to ":memory:" would open a new database.
+	conn.SetMaxOpenConns(1)
+
+	db := &SQLDatabase{conn: conn}
+
+	for _, statement := range schema {
+		_, err = conn.Exec(statement)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	err = seed(db)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// CreateQuiz will add quiz to the database.
+func (db *SQLDatabase) CreateQuiz(quiz *models.Quiz) error {
+	data, err := json.Marshal(quiz)
+	if err != nil {
+		return err
+	}
+
+	return db.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO quizzes (id, title_key, data) VALUES (?, ?, ?)`,
+			quiz.ID.String(), strings.ToLower(quiz.Title), string(data))
+		if err != nil {
+			return translateError(err)
+		}
+
+		return insertQuizVersion(tx, quiz, data)
+	})
+}
+
+// GetQuizByID will fetch quiz by given quizID.
+func (db *SQLDatabase) GetQuizByID(quizID uuid.UUID) (*models.Quiz, error) {
+	quiz := &models.Quiz{}
+	err := db.get(quiz, `SELECT data FROM quizzes WHERE id = ?`, quizID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// GetQuizByTitle will fetch quiz by given title, ignoring case.
+func (db *SQLDatabase) GetQuizByTitle(title string) (*models.Quiz, error) {
+	quiz := &models.Quiz{}
+	err := db.get(quiz, `SELECT data FROM quizzes WHERE title_key = ?`, strings.ToLower(title))
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// UpdateQuiz will replace stored quiz with the given quiz if it was not modified since it was read.
+func (db *SQLDatabase) UpdateQuiz(quiz *models.Quiz) error {
+	updated := *quiz
+	updated.Revision++
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+
+	err = db.transaction(func(tx *sql.Tx) error {
+		var version uint32
+		var revision uint64
+
+		err := tx.QueryRow(`SELECT COALESCE(json_extract(data, '$.version'), 0), COALESCE(json_extract(data, '$.revision'), 0)
+			FROM quizzes WHERE id = ?`, quiz.ID.String()).Scan(&version, &revision)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRecordNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if revision != quiz.Revision || (quiz.Version != version && quiz.Version != version+1) {
+			return ErrVersionConflict
+		}
+
+		_, err = tx.Exec(`UPDATE quizzes SET title_key = ?, data = ? WHERE id = ?`,
+			strings.ToLower(quiz.Title), string(data), quiz.ID.String())
+		if err != nil {
+			return translateError(err)
+		}
+
+		return insertQuizVersion(tx, &updated, data)
+	})
+	if err != nil {
+		return err
+	}
+
+	quiz.Revision = updated.Revision
+	return nil
+}
+
+// DeleteQuiz will remove quiz with given quizID along with all its versions, unless it has been attempted.
+func (db *SQLDatabase) DeleteQuiz(quizID uuid.UUID) error {
+	return db.transaction(func(tx *sql.Tx) error {
+		var attempted int
+
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM user_quiz_attempts WHERE quiz_id = ?)`, quizID.String()).Scan(&attempted)
+		if err != nil {
+			return err
+		}
+
+		if attempted == 1 {
+			return ErrRecordInUse
+		}
+
+		result, err := tx.Exec(`DELETE FROM quizzes WHERE id = ?`, quizID.String())
+		if err != nil {
+			return err
+		}
+
+		err = checkRowsAffected(result)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM quiz_versions WHERE quiz_id = ?`, quizID.String())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM quiz_version_media WHERE quiz_id = ?`, quizID.String())
+		return err
+	})
+}
+
+// GetQuizVersion will fetch snapshot of quiz as it was at given version.
+func (db *SQLDatabase) GetQuizVersion(quizID uuid.UUID, version uint32) (*models.Quiz, error) {
+	quiz := &models.Quiz{}
+	err := db.get(quiz, `SELECT data FROM quiz_versions WHERE quiz_id = ? AND version = ?`, quizID.String(), version)
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// ListQuizVersions will fetch snapshots of every version of quiz ordered by version.
+func (db *SQLDatabase) ListQuizVersions(quizID uuid.UUID) ([]models.Quiz, error) {
+	return db.queryQuizzes(`SELECT data FROM quiz_versions WHERE quiz_id = ? ORDER BY version`, quizID.String())
+}
+
+// ListQuizVersionsByMedia will fetch snapshots of every version of quizzes which attaches media.
+func (db *SQLDatabase) ListQuizVersionsByMedia(mediaID uuid.UUID) ([]models.Quiz, error) {
+	return db.queryQuizzes(`SELECT quiz_versions.data FROM quiz_version_media
+		JOIN quiz_versions USING (quiz_id, version)
+		WHERE quiz_version_media.media_id = ?`, mediaID.String())
+}
+
+// ListQuizzes will fetch quizzes matching the query along with total number of matching quizzes.
+func (db *SQLDatabase) ListQuizzes(query *models.QuizQuery) ([]models.Quiz, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query.Search != "" {
+		conditions = append(conditions, `instr(title_key, ?) > 0`)
+		args = append(args, strings.ToLower(query.Search))
+	}
+
+	if query.CreatedBy != nil {
+		conditions = append(conditions, `json_extract(data, '$.createdBy') = ?`)
+		args = append(args, query.CreatedBy.String())
+	}
+
+	if query.IsArchived != nil {
+		conditions = append(conditions, `COALESCE(json_extract(data, '$.isArchived'), 0) = ?`)
+		args = append(args, *query.IsArchived)
+	}
+
+	if query.Tag != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM json_each(quizzes.data, '$.tags') WHERE lower(value) = ?)`)
+		args = append(args, strings.ToLower(query.Tag))
+	}
+
+	if query.HasAttempted != nil {
+		condition := `EXISTS (SELECT 1 FROM user_quiz_attempts WHERE quiz_id = quizzes.id AND user_id = ?)`
+		if !*query.HasAttempted {
+			condition = "NOT " + condition
+		}
+
+		conditions = append(conditions, condition)
+		args = append(args, query.AttemptedBy.String())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM quizzes`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortColumn := quizSortColumns[query.SortBy]
+	comparison, order := ">", "ASC"
+	if query.Order == models.OrderDesc {
+		comparison, order = "<", "DESC"
+	}
+
+	if query.After != nil {
+		condition := "(" + sortColumn + ", id) " + comparison + " (?, ?)"
+		if where == "" {
+			where = " WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+		args = append(args, quizSortKey(query.After, query.SortBy), query.After.ID.String())
+	}
+
+	args = append(args, query.Limit)
+
+	quizzes, err := db.queryQuizzes(`SELECT data FROM quizzes`+where+
+		` ORDER BY `+sortColumn+` `+order+`, id `+order+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return quizzes, total, nil
+}
+
+// queryQuizzes will run query which selects data column of quizzes and decode every row.
+func (db *SQLDatabase) queryQuizzes(query string, args ...interface{}) ([]models.Quiz, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quizzes := []models.Quiz{}
+
+	for rows.Next() {
+		var data string
+
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		quiz := models.Quiz{}
+		err = json.Unmarshal([]byte(data), &quiz)
+		if err != nil {
+			return nil, err
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, rows.Err()
+}
+
+// insertQuizVersion will store snapshot of quiz version unless it already exists.
+func insertQuizVersion(tx *sql.Tx, quiz *models.Quiz, data []byte) error {
+	// quizzes created before versioning was introduced do not have a version.
+	if quiz.Version == 0 {
+		return nil
+	}
+
+	result, err := tx.Exec(`INSERT OR IGNORE INTO quiz_versions (quiz_id, version, data) VALUES (?, ?, ?)`,
+		quiz.ID.String(), quiz.Version, string(data))
+	if err != nil {
+		return err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return err
+	}
+
+	for _, mediaID := range quiz.MediaIDs() {
+		_, err = tx.Exec(`INSERT OR IGNORE INTO quiz_version_media (media_id, quiz_id, version) VALUES (?, ?, ?)`,
+			mediaID.String(), quiz.ID.String(), quiz.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateUser will add user to the database.
+func (db *SQLDatabase) CreateUser(user *models.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(`INSERT INTO users (id, username_key, data) VALUES (?, ?, ?)`,
+		user.ID.String(), strings.ToLower(user.Username), string(data))
+	return translateError(err)
+}
+
+// GetUserByID will fetch user by given userID.
+func (db *SQLDatabase) GetUserByID(userID uuid.UUID) (*models.User, error) {
+	user := &models.User{}
+	err := db.get(user, `SELECT data FROM users WHERE id = ?`, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername will fetch user by given username, ignoring case.
+func (db *SQLDatabase) GetUserByUsername(username string) (*models.User, error) {
+	user := &models.User{}
+	err := db.get(user, `SELECT data FROM users WHERE username_key = ?`, strings.ToLower(username))
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// CreateAttempt will add user quiz attempt to the database.
+func (db *SQLDatabase) CreateAttempt(attempt *models.UserQuizAttempts) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+
+	// quiz is checked in the same transaction so that an attempt is never created for a quiz being deleted.
+	return db.transaction(func(tx *sql.Tx) error {
+		var exists int
+
+		err := tx.QueryRow(`SELECT 1 FROM quizzes WHERE id = ?`, attempt.QuizID.String()).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRecordNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO user_quiz_attempts (id, user_id, quiz_id, version, data) VALUES (?, ?, ?, ?, ?)`,
+			attempt.ID.String(), attempt.UserID.String(), attempt.QuizID.String(), attempt.Version, string(data))
+		if err != nil {
+			return translateError(err)
+		}
+
+		return indexBankQuestions(tx, attempt)
+	})
+}
+
+// GetAttemptByID will fetch user quiz attempt by given attemptID.
+func (db *SQLDatabase) GetAttemptByID(attemptID uuid.UUID) (*models.UserQuizAttempts, error) {
+	attempt := &models.UserQuizAttempts{}
+	err := db.get(attempt, `SELECT data FROM user_quiz_attempts WHERE id = ?`, attemptID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// GetAttemptByUserAndQuiz will fetch latest attempt of given quiz made by given user.
+func (db *SQLDatabase) GetAttemptByUserAndQuiz(userID, quizID uuid.UUID) (*models.UserQuizAttempts, error) {
+	attempt := &models.UserQuizAttempts{}
+	err := db.get(attempt, `SELECT data FROM user_quiz_attempts WHERE user_id = ? AND quiz_id = ?
+		ORDER BY COALESCE(json_extract(data, '$.attemptNumber'), 1) DESC LIMIT 1`,
+		userID.String(), quizID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// ListAttemptsByUserAndQuiz will fetch all attempts of given quiz made by given user sorted by attempt number.
+func (db *SQLDatabase) ListAttemptsByUserAndQuiz(userID, quizID uuid.UUID) ([]models.UserQuizAttempts, error) {
+	return db.queryAttempts(`SELECT data FROM user_quiz_attempts WHERE user_id = ? AND quiz_id = ?
+		ORDER BY COALESCE(json_extract(data, '$.attemptNumber'), 1)`,
+		userID.String(), quizID.String())
+}
+
+// CountAttemptsByQuiz will return number of attempts made for given quiz by all users.
+func (db *SQLDatabase) CountAttemptsByQuiz(quizID uuid.UUID) (int, error) {
+	count := 0
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM user_quiz_attempts WHERE quiz_id = ?`, quizID.String()).Scan(&count)
+	return count, err
+}
+
+// UpdateAttempt will replace stored attempt with the given attempt if it was not modified since it was read.
+func (db *SQLDatabase) UpdateAttempt(attempt *models.UserQuizAttempts) error {
+	updated := *attempt
+	updated.Version++
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+
+	err = db.transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE user_quiz_attempts SET data = ?, version = ? WHERE id = ? AND version = ?`,
+			string(data), updated.Version, attempt.ID.String(), attempt.Version)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			var exists int
+
+			err = tx.QueryRow(`SELECT 1 FROM user_quiz_attempts WHERE id = ?`, attempt.ID.String()).Scan(&exists)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrRecordNotFound
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return ErrVersionConflict
+		}
+
+		return indexBankQuestions(tx, &updated)
+	})
+	if err != nil {
+		return err
+	}
+
+	attempt.Version = updated.Version
+	return nil
+}
+
+// indexBankQuestions will record that attempt has responses for questions copied from bank questions.
+func indexBankQuestions(tx *sql.Tx, attempt *models.UserQuizAttempts) error {
+	for _, response := range attempt.UserResponses {
+		if response.BankQuestionID == nil {
+			continue
+		}
+
+		_, err := tx.Exec(`INSERT OR IGNORE INTO attempt_bank_questions (bank_question_id, attempt_id) VALUES (?, ?)`,
+			response.BankQuestionID.String(), attempt.ID.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListExpiredAttempts will fetch attempts which have not ended and expire at or before given time.
+func (db *SQLDatabase) ListExpiredAttempts(before time.Time) ([]models.UserQuizAttempts, error) {
+	return db.queryAttempts(`SELECT data FROM user_quiz_attempts WHERE json_extract(data, '$.endAt') IS NULL
+		AND (json_extract(data, '$.expiresAt') IS NULL OR unixepoch(json_extract(data, '$.expiresAt'), 'subsec') <= ?)`,
+		float64(before.UnixMilli())/1000)
+}
+
+// queryAttempts will run query selecting data column of attempts and decode every row.
+func (db *SQLDatabase) queryAttempts(query string, args ...interface{}) ([]models.UserQuizAttempts, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []models.UserQuizAttempts{}
+
+	for rows.Next() {
+		var data string
+
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt := models.UserQuizAttempts{}
+		err = json.Unmarshal([]byte(data), &attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ListResponsesByBankQuestion will fetch responses of all attempts submitted for questions copied from given bank question.
+func (db *SQLDatabase) ListResponsesByBankQuestion(bankQuestionID uuid.UUID) ([]models.UserResponse, error) {
+	rows, err := db.conn.Query(`SELECT response.value FROM attempt_bank_questions
+		JOIN user_quiz_attempts ON user_quiz_attempts.id = attempt_bank_questions.attempt_id,
+		json_each(user_quiz_attempts.data, '$.userResponses') AS response
+		WHERE attempt_bank_questions.bank_question_id = ? AND json_extract(response.value, '$.bankQuestionID') = ?`,
+		bankQuestionID.String(), bankQuestionID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	responses := []models.UserResponse{}
+
+	for rows.Next() {
+		var data string
+
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		response := models.UserResponse{}
+		err = json.Unmarshal([]byte(data), &response)
+		if err != nil {
+			return nil, err
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, rows.Err()
+}
+
+// CreateBankQuestion will add question to question bank.
+func (db *SQLDatabase) CreateBankQuestion(question *models.BankQuestion) error {
+	data, err := json.Marshal(question)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(`INSERT INTO bank_questions (id, created_by, data) VALUES (?, ?, ?)`,
+		question.ID.String(), question.CreatedBy.String(), string(data))
+	return translateError(err)
+}
+
+// GetBankQuestionByID will fetch question of question bank by given questionID.
+func (db *SQLDatabase) GetBankQuestionByID(questionID uuid.UUID) (*models.BankQuestion, error) {
+	question := &models.BankQuestion{}
+	err := db.get(question, `SELECT data FROM bank_questions WHERE id = ?`, questionID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// bankQuestionOrder is the sqlite ordering of question bank, by creation time in milliseconds and then by ID.
+// It is kept apart from quizSortColumns so that changes to sorting of quizzes do not change order of question bank.
+const bankQuestionOrder = `CAST(round(unixepoch(json_extract(data, '$.createdAt'), 'subsec') * 1000) AS INTEGER), id`
+
+// ListBankQuestions will fetch questions of question bank created by given user, optionally having given tag.
+func (db *SQLDatabase) ListBankQuestions(createdBy uuid.UUID, tag string) ([]models.BankQuestion, error) {
+	query := `SELECT data FROM bank_questions WHERE created_by = ?`
+	args := []interface{}{createdBy.String()}
+
+	if tag != "" {
+		query += ` AND EXISTS (SELECT 1 FROM json_each(bank_questions.data, '$.tags') WHERE lower(value) = ?)`
+		args = append(args, strings.ToLower(tag))
+	}
+
+	query += ` ORDER BY ` + bankQuestionOrder
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []models.BankQuestion{}
+
+	for rows.Next() {
+		var data string
+
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		question := models.BankQuestion{}
+		err = json.Unmarshal([]byte(data), &question)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+// UpdateBankQuestion will replace stored question of question bank with the given question.
+func (db *SQLDatabase) UpdateBankQuestion(question *models.BankQuestion) error {
+	data, err := json.Marshal(question)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.conn.Exec(`UPDATE bank_questions SET data = ? WHERE id = ?`, string(data), question.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+// DeleteBankQuestion will remove question of question bank with given questionID.
+func (db *SQLDatabase) DeleteBankQuestion(questionID uuid.UUID) error {
+	result, err := db.conn.Exec(`DELETE FROM bank_questions WHERE id = ?`, questionID.String())
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+// CreateMedia will add details of uploaded media to the database.
+func (db *SQLDatabase) CreateMedia(media *models.Media) error {
+	data, err := json.Marshal(media)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(`INSERT INTO media (id, data) VALUES (?, ?)`, media.ID.String(), string(data))
+	return translateError(err)
+}
+
+// GetMediaByID will fetch details of uploaded media by given mediaID.
+func (db *SQLDatabase) GetMediaByID(mediaID uuid.UUID) (*models.Media, error) {
+	media := &models.Media{}
+	err := db.get(media, `SELECT data FROM media WHERE id = ?`, mediaID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Close will close the underlying database connection.
+func (db *SQLDatabase) Close() error {
+	return db.conn.Close()
+}
+
+// transaction will run fn in a transaction, which is committed only if fn does not return an error.
+func (db *SQLDatabase) transaction(fn func(tx *sql.Tx) error) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// get will run given query and decode the JSON document it returns into dest.
+func (db *SQLDatabase) get(dest interface{}, query string, args ...interface{}) error {
+	var data string
+
+	err := db.conn.QueryRow(query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRecordNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(data), dest)
+}
+
+// checkRowsAffected will return ErrRecordNotFound if statement did not modify any row.
+func checkRowsAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// translateError will convert unique constraint violations into ErrDuplicateRecord.
+func translateError(err error) error {
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		return ErrDuplicateRecord
+	}
+
+	return err
+}
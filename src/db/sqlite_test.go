@@ -0,0 +1,106 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSQLiteIndexesBankQuestionsOfExistingAttempts will test that attempts stored before responses were indexed
+// by bank question are indexed when database is opened.
+func TestSQLiteIndexesBankQuestionsOfExistingAttempts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quiz.db")
+
+	database, err := NewSQLiteDatabase(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+	bankQuestionID := uuid.New()
+	startedAt := time.Now()
+
+	attempt := &models.UserQuizAttempts{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		QuizID:    quizID,
+		StartedAt: &startedAt,
+		UserResponses: []models.UserResponse{
+			{ID: uuid.New(), QuestionID: uuid.New(), BankQuestionID: &bankQuestionID},
+			{ID: uuid.New(), QuestionID: uuid.New()},
+		},
+	}
+	assert.Nil(t, database.CreateAttempt(attempt))
+
+	responses, err := database.ListResponsesByBankQuestion(bankQuestionID)
+	assert.Nil(t, err)
+	assert.Len(t, responses, 1)
+
+	// remove the index, as if attempt was stored before it was introduced.
+	_, err = database.conn.Exec(`DELETE FROM attempt_bank_questions`)
+	assert.Nil(t, err)
+	database.Close()
+
+	database, err = NewSQLiteDatabase(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer database.Close()
+
+	responses, err = database.ListResponsesByBankQuestion(bankQuestionID)
+	assert.Nil(t, err)
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, attempt.UserResponses[0].ID, responses[0].ID)
+	}
+}
+
+// TestSQLiteIndexesMediaOfExistingQuizVersions will test that versions of quizzes stored before media of quiz
+// versions were indexed are indexed when database is opened.
+func TestSQLiteIndexesMediaOfExistingQuizVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quiz.db")
+
+	database, err := NewSQLiteDatabase(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	questionMediaID, optionMediaID := uuid.New(), uuid.New()
+	quiz := &models.Quiz{
+		ID:      uuid.New(),
+		Title:   "Media quiz",
+		Version: 1,
+		Questions: []models.Question{{
+			ID:       uuid.New(),
+			MediaIDs: []uuid.UUID{questionMediaID},
+			Options:  []models.Option{{ID: uuid.New(), MediaIDs: []uuid.UUID{optionMediaID}}},
+		}},
+	}
+	assert.Nil(t, database.CreateQuiz(quiz))
+
+	// remove the index, as if quiz was stored before it was introduced.
+	_, err = database.conn.Exec(`DELETE FROM quiz_version_media`)
+	assert.Nil(t, err)
+	database.Close()
+
+	database, err = NewSQLiteDatabase(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer database.Close()
+
+	for _, mediaID := range []uuid.UUID{questionMediaID, optionMediaID} {
+		versions, err := database.ListQuizVersionsByMedia(mediaID)
+		assert.Nil(t, err)
+		if assert.Len(t, versions, 1) {
+			assert.Equal(t, quiz.ID, versions[0].ID)
+		}
+	}
+
+	versions, err := database.ListQuizVersionsByMedia(uuid.New())
+	assert.Nil(t, err)
+	assert.Empty(t, versions)
+}
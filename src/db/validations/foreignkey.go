@@ -7,24 +7,35 @@ import (
 	"github.com/shaileshhb/quiz/src/db"
 )
 
+// notFoundError is returned when a record referred by a request does not exist, it wraps db.ErrRecordNotFound.
+type notFoundError struct {
+	message string
+}
+
+func (e *notFoundError) Error() string {
+	return e.message
+}
+
+func (e *notFoundError) Unwrap() error {
+	return db.ErrRecordNotFound
+}
+
 // DoesUserIDExist will check if userID exist in the database, if not then return an error
-func DoesUserIDExist(database *db.Database, userID uuid.UUID) error {
-	for _, user := range database.Users {
-		if user.ID == userID {
-			return nil
-		}
+func DoesUserIDExist(database db.UserRepository, userID uuid.UUID) error {
+	_, err := database.GetUserByID(userID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return &notFoundError{message: "user not found"}
 	}
 
-	return errors.New("user not found")
+	return err
 }
 
 // DoesQuizIDExist will check if quizID exist in the database, if not then return an error
-func DoesQuizIDExist(database *db.Database, quizID uuid.UUID) error {
-	for _, quiz := range database.Quiz {
-		if quiz.ID == quizID {
-			return nil
-		}
+func DoesQuizIDExist(database db.QuizRepository, quizID uuid.UUID) error {
+	_, err := database.GetQuizByID(quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return &notFoundError{message: "quiz not found"}
 	}
 
-	return errors.New("quiz not found")
+	return err
 }
@@ -0,0 +1,478 @@
+package db
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// DefaultSnapshotEvery is the number of log records after which a compacted snapshot is taken.
+	DefaultSnapshotEvery = 1000
+
+	// maxRecordSize guards against allocating huge buffers when record length is corrupted.
+	maxRecordSize = 64 << 20
+)
+
+// operations recorded in the write-ahead log.
+const (
+	opQuizCreated    = "quizCreated"
+	opQuizUpdated    = "quizUpdated"
+	opQuizDeleted    = "quizDeleted"
+	opUserRegistered = "userRegistered"
+	opAttemptStarted = "attemptStarted"
+	opAttemptUpdated = "attemptUpdated" // response submitted
+
+	opBankQuestionCreated = "bankQuestionCreated"
+	opBankQuestionUpdated = "bankQuestionUpdated"
+	opBankQuestionDeleted = "bankQuestionDeleted"
+
+	opMediaCreated = "mediaCreated"
+)
+
+// logRecord is a single mutation appended to the write-ahead log.
+// On disk every record is framed as 4 byte length, 4 byte CRC32 checksum followed by the JSON encoded record.
+type logRecord struct {
+	Seq     uint64                   `json:"seq"`
+	Op      string                   `json:"op"`
+	Quiz    *models.Quiz             `json:"quiz,omitempty"`
+	User    *models.User             `json:"user,omitempty"`
+	Attempt *models.UserQuizAttempts `json:"attempt,omitempty"`
+
+	BankQuestion *models.BankQuestion `json:"bankQuestion,omitempty"`
+	Media        *models.Media        `json:"media,omitempty"`
+}
+
+// snapshot contains every record of the database along with the sequence of last log record it includes.
+type snapshot struct {
+	Seq          uint64                    `json:"seq"`
+	Quizzes      []models.Quiz             `json:"quizzes"`
+	QuizVersions []models.Quiz             `json:"quizVersions"`
+	Users        []models.User             `json:"users"`
+	Attempts     []models.UserQuizAttempts `json:"attempts"`
+
+	BankQuestions []models.BankQuestion `json:"bankQuestions"`
+	Media         []models.Media        `json:"media"`
+}
+
+// CorruptLogError is returned by OpenDatabase when the tail of the write-ahead log is truncated or corrupted.
+// Records up to Offset were replayed and the rest of the log was discarded.
+type CorruptLogError struct {
+	Offset int64
+	Reason string
+}
+
+func (e *CorruptLogError) Error() string {
+	return fmt.Sprintf("write-ahead log corrupted at offset %d: %s", e.Offset, e.Reason)
+}
+
+// logFile is the file the write-ahead log is appended to, it is implemented by *os.File.
+type logFile interface {
+	io.Writer
+	io.Seeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// writeAheadLog appends mutations of the database to a file in dir.
+type writeAheadLog struct {
+	dir           string
+	file          logFile
+	seq           uint64
+	pending       int // records written since last snapshot
+	snapshotEvery int
+
+	// err is set when a failed append could not be rolled back, every later append fails with it
+	// since records appended after a partial frame would be discarded on replay.
+	err error
+}
+
+// OpenDatabase will open in-memory database persisted in dir. Latest snapshot and the write-ahead log
+// written after it are replayed, and dummy data is seeded only if it is not present.
+// A compacted snapshot is taken after every snapshotEvery mutations.
+//
+// If the tail of the log is corrupted the database is still returned, along with *CorruptLogError.
+func OpenDatabase(dir string, snapshotEvery int) (*Database, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	db := newDatabase()
+
+	seq, err := db.loadSnapshot(filepath.Join(dir, snapshotFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, offset, recoveryErr := db.replay(file, seq)
+	if recoveryErr != nil {
+		// drop the corrupted tail so that new records are not appended after it.
+		err = file.Truncate(offset)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	db.wal = &writeAheadLog{
+		dir:           dir,
+		file:          file,
+		seq:           seq,
+		snapshotEvery: snapshotEvery,
+	}
+
+	err = seed(db)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if recoveryErr != nil {
+		return db, recoveryErr
+	}
+
+	return db, nil
+}
+
+// write will append record to the log and apply it, caller must hold mu.
+func (db *Database) write(record logRecord) error {
+	if db.wal != nil {
+		err := db.wal.append(&record)
+		if err != nil {
+			return err
+		}
+	}
+
+	db.apply(record)
+
+	if db.wal != nil && db.wal.pending >= db.wal.snapshotEvery {
+		// record is already durable in the log, so a failed compaction is retried on next write.
+		_ = db.takeSnapshot()
+	}
+
+	return nil
+}
+
+// apply will update in-memory records for given log record, caller must hold mu.
+func (db *Database) apply(record logRecord) {
+	switch record.Op {
+	case opQuizCreated, opQuizUpdated:
+		db.putQuiz(*record.Quiz)
+	case opQuizDeleted:
+		db.removeQuiz(record.Quiz.ID)
+		db.removeQuizVersions(record.Quiz.ID)
+	case opUserRegistered:
+		db.putUser(*record.User)
+	case opAttemptStarted, opAttemptUpdated:
+		db.putAttempt(*record.Attempt)
+	case opBankQuestionCreated, opBankQuestionUpdated:
+		db.bankQuestions[record.BankQuestion.ID] = cloneBankQuestion(*record.BankQuestion)
+	case opBankQuestionDeleted:
+		delete(db.bankQuestions, record.BankQuestion.ID)
+	case opMediaCreated:
+		db.media[record.Media.ID] = *record.Media
+	}
+}
+
+// replay will apply every record in file written after snapshotSeq. It returns sequence of the last record
+// and offset up to which file is valid. *CorruptLogError is returned if the file has a truncated or corrupted tail.
+func (db *Database) replay(file *os.File, snapshotSeq uint64) (uint64, int64, error) {
+	reader := bufio.NewReader(file)
+	seq := snapshotSeq
+	offset := int64(0)
+	header := make([]byte, 8)
+
+	for {
+		_, err := io.ReadFull(reader, header)
+		if errors.Is(err, io.EOF) {
+			return seq, offset, nil
+		}
+
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return seq, offset, &CorruptLogError{Offset: offset, Reason: "truncated record header"}
+		}
+
+		if err != nil {
+			return seq, offset, err
+		}
+
+		length := binary.BigEndian.Uint32(header[:4])
+		checksum := binary.BigEndian.Uint32(header[4:])
+
+		if length > maxRecordSize {
+			return seq, offset, &CorruptLogError{Offset: offset, Reason: "invalid record length"}
+		}
+
+		payload := make([]byte, length)
+		_, err = io.ReadFull(reader, payload)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return seq, offset, &CorruptLogError{Offset: offset, Reason: "truncated record"}
+		}
+
+		if err != nil {
+			return seq, offset, err
+		}
+
+		if crc32.ChecksumIEEE(payload) != checksum {
+			return seq, offset, &CorruptLogError{Offset: offset, Reason: "checksum mismatch"}
+		}
+
+		record := logRecord{}
+		err = json.Unmarshal(payload, &record)
+		if err != nil || !record.isValid() {
+			return seq, offset, &CorruptLogError{Offset: offset, Reason: "invalid record"}
+		}
+
+		// records up to snapshot sequence are already part of the snapshot.
+		if record.Seq > seq {
+			db.apply(record)
+			seq = record.Seq
+		}
+
+		offset += int64(len(header)) + int64(length)
+	}
+}
+
+// isValid will check that record has a known operation along with the data it needs.
+func (record *logRecord) isValid() bool {
+	switch record.Op {
+	case opQuizCreated, opQuizUpdated, opQuizDeleted:
+		return record.Quiz != nil
+	case opUserRegistered:
+		return record.User != nil
+	case opAttemptStarted, opAttemptUpdated:
+		return record.Attempt != nil
+	case opBankQuestionCreated, opBankQuestionUpdated, opBankQuestionDeleted:
+		return record.BankQuestion != nil
+	case opMediaCreated:
+		return record.Media != nil
+	default:
+		return false
+	}
+}
+
+// loadSnapshot will load snapshot from path if it exists and return sequence of the last record it includes.
+func (db *Database) loadSnapshot(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	snap := snapshot{}
+	err = json.Unmarshal(data, &snap)
+	if err != nil {
+		return 0, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+
+	for _, quiz := range snap.QuizVersions {
+		db.putQuizVersion(quiz)
+	}
+
+	for _, quiz := range snap.Quizzes {
+		db.putQuiz(quiz)
+	}
+
+	for _, user := range snap.Users {
+		db.putUser(user)
+	}
+
+	for _, attempt := range snap.Attempts {
+		db.putAttempt(attempt)
+	}
+
+	for _, question := range snap.BankQuestions {
+		db.bankQuestions[question.ID] = question
+	}
+
+	for _, media := range snap.Media {
+		db.media[media.ID] = media
+	}
+
+	return snap.Seq, nil
+}
+
+// takeSnapshot will write all records to a new snapshot and truncate the log, caller must hold mu.
+// Snapshot is written to a temporary file and renamed so that a crash never leaves a partial snapshot.
+func (db *Database) takeSnapshot() error {
+	snap := snapshot{
+		Seq:      db.wal.seq,
+		Quizzes:  make([]models.Quiz, 0, len(db.quizzes)),
+		Users:    make([]models.User, 0, len(db.users)),
+		Attempts: make([]models.UserQuizAttempts, 0, len(db.attempts)),
+
+		BankQuestions: make([]models.BankQuestion, 0, len(db.bankQuestions)),
+		Media:         make([]models.Media, 0, len(db.media)),
+	}
+
+	for _, quiz := range db.quizzes {
+		snap.Quizzes = append(snap.Quizzes, quiz)
+	}
+
+	for _, versions := range db.quizVersions {
+		for _, quiz := range versions {
+			snap.QuizVersions = append(snap.QuizVersions, quiz)
+		}
+	}
+
+	for _, user := range db.users {
+		snap.Users = append(snap.Users, user)
+	}
+
+	for _, attempt := range db.attempts {
+		snap.Attempts = append(snap.Attempts, attempt)
+	}
+
+	for _, question := range db.bankQuestions {
+		snap.BankQuestions = append(snap.BankQuestions, question)
+	}
+
+	for _, media := range db.media {
+		snap.Media = append(snap.Media, media)
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(db.wal.dir, snapshotFileName)
+	err = writeFileSync(path+".tmp", data)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return err
+	}
+
+	// records in the log are now part of snapshot, and are skipped by sequence if truncate fails.
+	err = db.wal.file.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.wal.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	db.wal.pending = 0
+	return nil
+}
+
+// append will write record to the end of log and flush it to disk. If the record can not be written completely
+// the log is truncated back to where the record started.
+func (wal *writeAheadLog) append(record *logRecord) error {
+	if wal.err != nil {
+		return wal.err
+	}
+
+	record.Seq = wal.seq + 1
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	copy(frame[8:], payload)
+
+	offset, err := wal.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = wal.file.Write(frame)
+	if err == nil {
+		err = wal.file.Sync()
+	}
+
+	if err != nil {
+		wal.rollback(offset)
+		return err
+	}
+
+	wal.seq = record.Seq
+	wal.pending++
+	return nil
+}
+
+// rollback will remove partially written record starting at offset, or make the log unusable if it can not.
+func (wal *writeAheadLog) rollback(offset int64) {
+	err := wal.file.Truncate(offset)
+	if err == nil {
+		_, err = wal.file.Seek(offset, io.SeekStart)
+	}
+
+	if err != nil {
+		wal.err = fmt.Errorf("write-ahead log is unusable after a failed append: %w", err)
+	}
+}
+
+// close will flush the log to disk and close it.
+func (wal *writeAheadLog) close() error {
+	err := wal.file.Sync()
+	if err != nil {
+		wal.file.Close()
+		return err
+	}
+
+	return wal.file.Close()
+}
+
+// writeFileSync will write data to path and flush it to disk.
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
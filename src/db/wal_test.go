@@ -0,0 +1,222 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOpenDatabaseReplay will test that records are replayed from the log after reopening database.
+func TestOpenDatabaseReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	database, err := OpenDatabase(dir, 100)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	attempt := createTestAttempt(t, database)
+	database.Close()
+
+	database, err = OpenDatabase(dir, 100)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer database.Close()
+
+	stored, err := database.GetAttemptByID(attempt.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, attempt.Version, stored.Version)
+	assert.Equal(t, 1.0, stored.TotalScore)
+
+	// dummy data is replayed instead of being seeded again.
+	assert.Equal(t, 2, len(database.users))
+	assert.Equal(t, 1, len(database.quizzes))
+}
+
+// TestOpenDatabaseSnapshot will test that log is compacted into snapshot and replayed after reopening database.
+func TestOpenDatabaseSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	database, err := OpenDatabase(dir, 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	attempt := createTestAttempt(t, database)
+	database.Close()
+
+	_, err = os.Stat(filepath.Join(dir, snapshotFileName))
+	assert.Nil(t, err)
+
+	database, err = OpenDatabase(dir, 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer database.Close()
+
+	stored, err := database.GetAttemptByID(attempt.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, attempt.Version, stored.Version)
+	assert.Equal(t, 2, len(database.users))
+	assert.Equal(t, 1, len(database.quizzes))
+}
+
+// TestOpenDatabaseCorruptedTail will test that truncated and corrupted records are reported and discarded.
+func TestOpenDatabaseCorruptedTail(t *testing.T) {
+	tests := map[string]func(data []byte) []byte{
+		"truncated record": func(data []byte) []byte {
+			return data[:len(data)-5]
+		},
+		"checksum mismatch": func(data []byte) []byte {
+			data[len(data)-2] ^= 0xff
+			return data
+		},
+		"garbage": func(data []byte) []byte {
+			return append(data, 0, 0, 0)
+		},
+	}
+
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			database, err := OpenDatabase(dir, 100)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			attempt := createTestAttempt(t, database)
+			database.Close()
+
+			path := filepath.Join(dir, walFileName)
+			data, _ := os.ReadFile(path)
+			_ = os.WriteFile(path, corrupt(data), 0o644)
+
+			database, err = OpenDatabase(dir, 100)
+
+			var corruptLogErr *CorruptLogError
+			assert.True(t, errors.As(err, &corruptLogErr))
+			if !assert.NotNil(t, database) {
+				return
+			}
+
+			_, err = database.GetAttemptByID(attempt.ID)
+			assert.Nil(t, err)
+
+			// log remains usable after the corrupted tail is discarded.
+			attempt.UserID = uuid.New()
+			attempt.ID = uuid.New()
+			assert.Nil(t, database.CreateAttempt(attempt))
+			database.Close()
+
+			database, err = OpenDatabase(dir, 100)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer database.Close()
+
+			_, err = database.GetAttemptByID(attempt.ID)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// tornFile will write only half of the next record when failWrite is set, and fail to truncate when failTruncate is set.
+type tornFile struct {
+	logFile
+	failWrite    bool
+	failTruncate bool
+}
+
+func (f *tornFile) Write(data []byte) (int, error) {
+	if !f.failWrite {
+		return f.logFile.Write(data)
+	}
+
+	f.failWrite = false
+	n, _ := f.logFile.Write(data[:len(data)/2])
+	return n, errors.New("no space left on device")
+}
+
+func (f *tornFile) Truncate(size int64) error {
+	if f.failTruncate {
+		return errors.New("input/output error")
+	}
+
+	return f.logFile.Truncate(size)
+}
+
+// TestFailedAppend will test that a partially written record is removed, so that records appended after it
+// are still replayed. If it can not be removed, every later append fails.
+func TestFailedAppend(t *testing.T) {
+	dir := t.TempDir()
+
+	database, err := OpenDatabase(dir, 100)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	file := &tornFile{logFile: database.wal.file, failWrite: true}
+	database.wal.file = file
+
+	user := &models.User{ID: uuid.New(), Username: "torn"}
+	assert.NotNil(t, database.CreateUser(user))
+
+	_, err = database.GetUserByID(user.ID)
+	assert.Equal(t, ErrRecordNotFound, err)
+
+	attempt := createTestAttempt(t, database)
+	database.Close()
+
+	database, err = OpenDatabase(dir, 100)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = database.GetAttemptByID(attempt.ID)
+	assert.Nil(t, err)
+
+	file = &tornFile{logFile: database.wal.file, failWrite: true, failTruncate: true}
+	database.wal.file = file
+
+	assert.NotNil(t, database.CreateUser(user))
+	err = database.CreateUser(&models.User{ID: uuid.New(), Username: "after torn"})
+	assert.ErrorContains(t, err, "write-ahead log is unusable")
+	database.Close()
+
+	// only the partial record is discarded when the database is opened again.
+	database, err = OpenDatabase(dir, 100)
+	var corruptLogErr *CorruptLogError
+	assert.True(t, errors.As(err, &corruptLogErr))
+	if assert.NotNil(t, database) {
+		database.Close()
+	}
+}
+
+// createTestAttempt will start and update an attempt of the dummy quiz.
+func createTestAttempt(t *testing.T, database *Database) *models.UserQuizAttempts {
+	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+	startedAt := time.Now()
+
+	attempt := &models.UserQuizAttempts{
+		ID:        uuid.New(),
+		UserID:    userID,
+		QuizID:    quizID,
+		StartedAt: &startedAt,
+	}
+
+	assert.Nil(t, database.CreateAttempt(attempt))
+
+	attempt.TotalScore++
+	assert.Nil(t, database.UpdateAttempt(attempt))
+
+	return attempt
+}
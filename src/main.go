@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,6 +10,7 @@ import (
 	"github.com/shaileshhb/quiz/src/db"
 	"github.com/shaileshhb/quiz/src/log"
 	"github.com/shaileshhb/quiz/src/server"
+	"github.com/shaileshhb/quiz/src/storage"
 )
 
 func main() {
@@ -19,17 +21,63 @@ func main() {
 		return
 	}
 
-	database := db.NewDatabase()
-	ser := server.NewServer(logger, database)
+	database, err := db.Open(os.Getenv("DB_DRIVER"), os.Getenv("DB_DSN"))
+
+	var corruptLogErr *db.CorruptLogError
+	if errors.As(err, &corruptLogErr) {
+		logger.Warn().Err(err).Msg("Discarded corrupted tail of write-ahead log")
+		err = nil
+	}
+
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Error opening database")
+		return
+	}
+
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+
+	mediaStorage, err := storage.NewLocalStorage(mediaDir)
+	if err != nil {
+		database.Close()
+		logger.Fatal().Err(err).Msg("Error opening media storage")
+		return
+	}
+
+	ser := server.NewServer(logger, database, mediaStorage)
 	ser.InitializeRouter()
 
 	ser.RegisterModuleRoutes()
 
-	logger.Error().Err(ser.App.Listen(":8080")).Msg("")
+	ser.ExpiryScheduler.Start()
 
-	// Stop Server On System Call or Interrupt.
+	// Stop Server On System Call or Interrupt, Listen returns once requests in progress are finished.
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-	<-ch
-	os.Exit(0)
+
+	go func() {
+		<-ch
+		logger.Info().Msg("Shutting down server")
+
+		err := ser.App.Shutdown()
+		if err != nil {
+			logger.Error().Err(err).Msg("Error shutting down server")
+		}
+	}()
+
+	err = ser.App.Listen(":8080")
+	if err != nil {
+		logger.Error().Err(err).Msg("")
+	}
+
+	// scheduler is stopped before closing database so that it does not update attempts after it is closed.
+	ser.ExpiryScheduler.Stop()
+
+	err = database.Close()
+	if err != nil {
+		logger.Error().Err(err).Msg("Error closing database")
+		os.Exit(1)
+	}
 }
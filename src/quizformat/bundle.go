@@ -0,0 +1,39 @@
+package quizformat
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// BundleVersion is incremented whenever the structure of JSON bundles changes in a way older readers can not handle.
+const BundleVersion = 1
+
+// bundleFormat identifies JSON files which are quiz bundles.
+const bundleFormat = "quiz-bundle"
+
+// Bundle is a portable JSON file containing a quiz with its correct answers and content of its media,
+// which can be used to move quizzes between environments.
+type Bundle struct {
+	Format     string      `json:"format"`
+	Version    int         `json:"version"`
+	ExportedAt time.Time   `json:"exportedAt"`
+	Quiz       models.Quiz `json:"quiz"`
+	Media      []MediaFile `json:"media"`
+}
+
+// writeBundle will write quiz along with content of its media to a JSON bundle.
+func writeBundle(quiz *models.Quiz, media []MediaFile) ([]byte, error) {
+	if media == nil {
+		media = []MediaFile{}
+	}
+
+	return json.MarshalIndent(Bundle{
+		Format:     bundleFormat,
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Quiz:       *quiz,
+		Media:      media,
+	}, "", "  ")
+}
@@ -0,0 +1,230 @@
+package quizformat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// csvOptionPrefix starts names of option columns such as "option 1" once spaces are removed.
+const csvOptionPrefix = "option"
+
+// csvColumns will contain index of every known column of a CSV file.
+type csvColumns struct {
+	named   map[string]int // normalized column name to index
+	options []csvOption    // sorted by option number
+}
+
+// csvOption is a column containing an option, number is the number in its header e.g. 2 for "Option 2".
+type csvOption struct {
+	number int
+	index  int
+}
+
+// readCSV will read quizzes from a CSV file with a header row, where every following row is a question.
+// Header names are matched ignoring case, spaces, underscores and hyphens. Only the question column is required:
+//   - quiz: title of the quiz, questions of rows without it are added to the default quiz.
+//   - question, type, points, pool and explanation: fields of the question.
+//   - option 1 to option 10: answers of choice questions.
+//   - correct: numbers of correct options separated by commas, accepted answers of short text questions
+//     separated by "|", answer of numeric questions, or true or false for true/false questions without options.
+//   - tolerance: allowed difference from answer of numeric questions.
+func readCSV(content io.Reader, defaultTitle string) (*Import, error) {
+	csvReader := csv.NewReader(content)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file must start with a header row")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	columns, err := parseCSVHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newImportBuilder(defaultTitle)
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			builder.fail("", parseErr.StartLine, parseErr.Err)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		title := columns.value(record, "quiz")
+
+		question, err := columns.question(record)
+		if err != nil {
+			builder.fail(title, line, err)
+			continue
+		}
+
+		builder.add(title, line, *question)
+	}
+
+	return builder.imported, nil
+}
+
+// parseCSVHeader will find index of every known column in header.
+func parseCSVHeader(header []string) (*csvColumns, error) {
+	columns := &csvColumns{named: map[string]int{}}
+
+	for i, name := range header {
+		name = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+
+		if number, err := strconv.Atoi(strings.TrimPrefix(name, csvOptionPrefix)); err == nil &&
+			strings.HasPrefix(name, csvOptionPrefix) {
+			columns.options = append(columns.options, csvOption{number: number, index: i})
+			continue
+		}
+
+		columns.named[name] = i
+	}
+
+	if _, ok := columns.named["question"]; !ok {
+		return nil, errors.New("csv header must contain a question column")
+	}
+
+	sort.Slice(columns.options, func(i, j int) bool {
+		return columns.options[i].number < columns.options[j].number
+	})
+
+	return columns, nil
+}
+
+// value will return trimmed value of named column of record, or empty string if record does not have the column.
+func (columns *csvColumns) value(record []string, name string) string {
+	i, ok := columns.named[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+// question will map a row of the CSV file onto a question.
+func (columns *csvColumns) question(record []string) (*models.Question, error) {
+	question := &models.Question{
+		Text:        columns.value(record, "question"),
+		Type:        columns.value(record, "type"),
+		Pool:        columns.value(record, "pool"),
+		Explanation: columns.value(record, "explanation"),
+	}
+
+	var err error
+
+	if points := columns.value(record, "points"); points != "" {
+		question.Points, err = strconv.ParseFloat(points, 64)
+		if err != nil {
+			return nil, errors.New("points must be a number")
+		}
+	}
+
+	correct := columns.value(record, "correct")
+
+	switch question.Type {
+	case models.QuestionTypeShortText:
+		for _, answer := range strings.Split(correct, "|") {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, answer)
+			}
+		}
+
+		return question, nil
+	case models.QuestionTypeNumeric:
+		answer, err := strconv.ParseFloat(correct, 64)
+		if err != nil {
+			return nil, errors.New("correct answer of numeric question must be a number")
+		}
+		question.NumericAnswer = &answer
+
+		if tolerance := columns.value(record, "tolerance"); tolerance != "" {
+			question.Tolerance, err = strconv.ParseFloat(tolerance, 64)
+			if err != nil {
+				return nil, errors.New("tolerance must be a number")
+			}
+		}
+
+		return question, nil
+	}
+
+	numbers := map[int]int{} // option number to index of option in question
+	for _, column := range columns.options {
+		if column.index < len(record) && strings.TrimSpace(record[column.index]) != "" {
+			numbers[column.number] = len(question.Options)
+			question.Options = append(question.Options, models.Option{Answer: strings.TrimSpace(record[column.index])})
+		}
+	}
+
+	if question.Type == models.QuestionTypeTrueFalse && len(question.Options) == 0 {
+		if !strings.EqualFold(correct, "true") && !strings.EqualFold(correct, "false") {
+			return nil, errors.New("correct answer of true/false question must be true or false")
+		}
+
+		question.Options = trueFalseOptions(strings.EqualFold(correct, "true"))
+		return question, nil
+	}
+
+	for i := range question.Options {
+		question.Options[i].IsCorrect = new(bool)
+	}
+
+	for _, number := range strings.FieldsFunc(correct, func(r rune) bool { return r == ',' || r == ';' }) {
+		optionNumber, err := strconv.Atoi(strings.TrimSpace(number))
+		i, ok := numbers[optionNumber]
+		if err != nil || !ok {
+			return nil, fmt.Errorf("correct option %s does not exist", strings.TrimSpace(number))
+		}
+
+		*question.Options[i].IsCorrect = true
+	}
+
+	return question, nil
+}
+
+// trueFalseOptions will return options of a true/false question, where the first option is true.
+func trueFalseOptions(isTrue bool) []models.Option {
+	isFalse := !isTrue
+
+	return []models.Option{
+		{Answer: "True", IsCorrect: &isTrue},
+		{Answer: "False", IsCorrect: &isFalse},
+	}
+}
+
+// isEmptyRecord will check if every field of record is empty.
+func isEmptyRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+
+	return true
+}
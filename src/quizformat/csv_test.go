@@ -0,0 +1,70 @@
+package quizformat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadCSV will test that rows are mapped onto questions grouped into quizzes by the quiz column.
+func TestReadCSV(t *testing.T) {
+	content := `Quiz,Question,Type,Points,Option 1,Option 2,Option 3,Correct,Tolerance
+Capitals,Capital of France?,,2,Paris,London,Delhi,1,
+,Which are in Asia?,multipleChoice,,Delhi,Tokyo,Paris,"1, 2",
+Capitals,Capital of Japan?,shortText,,,,,Tokyo|Tokio,
+Science,Value of pi?,numeric,,,,,3.14,0.01
+Science,Water is wet,trueFalse,,,,,TRUE,
+`
+
+	imported, err := Read(FormatCSV, strings.NewReader(content), "General")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Empty(t, imported.Errors)
+	assert.Len(t, imported.Quizzes, 3)
+
+	capitals := imported.Quizzes[0]
+	assert.Equal(t, "Capitals", capitals.Quiz.Title)
+	assert.Equal(t, []int{2, 4}, capitals.Lines)
+	assert.Equal(t, 2.0, capitals.Quiz.Questions[0].Points)
+	assert.Len(t, capitals.Quiz.Questions[0].Options, 3)
+	assert.True(t, *capitals.Quiz.Questions[0].Options[0].IsCorrect)
+	assert.False(t, *capitals.Quiz.Questions[0].Options[1].IsCorrect)
+	assert.Equal(t, []string{"Tokyo", "Tokio"}, capitals.Quiz.Questions[1].AcceptedAnswers)
+
+	general := imported.Quizzes[1]
+	assert.Equal(t, "General", general.Quiz.Title)
+	assert.True(t, *general.Quiz.Questions[0].Options[1].IsCorrect)
+	assert.False(t, *general.Quiz.Questions[0].Options[2].IsCorrect)
+
+	science := imported.Quizzes[2]
+	assert.Equal(t, 3.14, *science.Quiz.Questions[0].NumericAnswer)
+	assert.Equal(t, 0.01, science.Quiz.Questions[0].Tolerance)
+	assert.Equal(t, models.QuestionTypeTrueFalse, science.Quiz.Questions[1].Type)
+	assert.True(t, *science.Quiz.Questions[1].Options[0].IsCorrect)
+}
+
+// TestReadCSVErrors will test that every invalid row is reported along with its line.
+func TestReadCSVErrors(t *testing.T) {
+	content := "question,points,option1,option2,correct\n" +
+		"Capital of France?,two,Paris,London,1\n" +
+		"Capital of India?,1,Delhi,London,3\n" +
+		"Capital of Japan?,1,Tokyo,Delhi,1\n"
+
+	imported, err := Read(FormatCSV, strings.NewReader(content), "Capitals")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, []models.ImportError{
+		{Line: 2, Quiz: "Capitals", Error: "points must be a number"},
+		{Line: 3, Quiz: "Capitals", Error: "correct option 3 does not exist"},
+	}, imported.Errors)
+	assert.Len(t, imported.Quizzes[0].Quiz.Questions, 1)
+
+	_, err = Read(FormatCSV, strings.NewReader("title,answer\n"), "Capitals")
+	assert.Equal(t, "csv header must contain a question column", err.Error())
+}
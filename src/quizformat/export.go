@@ -0,0 +1,86 @@
+package quizformat
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// Formats which quizzes can be exported to, besides FormatGIFT.
+const (
+	FormatQTI  = "qti"
+	FormatJSON = "json"
+)
+
+// MediaFile will contain details and content of media attached to an exported quiz.
+type MediaFile struct {
+	models.Media
+	Content []byte `json:"content"` // encoded in base64 in JSON
+}
+
+// Export will contain a file which quiz was exported to.
+type Export struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// writer will write quiz along with content of its media to a file of a specific format.
+type writer struct {
+	write       func(quiz *models.Quiz, media []MediaFile) ([]byte, error)
+	extension   string
+	contentType string
+	withMedia   bool // whether the format includes content of media
+}
+
+var writers = map[string]writer{
+	FormatQTI:  {write: writeQTI, extension: ".zip", contentType: "application/zip", withMedia: true},
+	FormatJSON: {write: writeBundle, extension: ".json", contentType: "application/json", withMedia: true},
+	FormatGIFT: {write: writeGIFT, extension: ".gift.txt", contentType: "text/plain; charset=utf-8"},
+}
+
+// Write will export quiz along with its correct answers to a file of given format.
+// Media is the content of every media attached to quiz, it is ignored by formats which can not include it.
+func Write(format string, quiz *models.Quiz, media []MediaFile) (*Export, error) {
+	w, ok := writers[format]
+	if !ok {
+		return nil, errors.New("format must be one of qti, json or gift")
+	}
+
+	content, err := w.write(quiz, media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Export{
+		FileName:    exportFileName(quiz.Title) + w.extension,
+		ContentType: w.contentType,
+		Content:     content,
+	}, nil
+}
+
+// IncludesMedia will check if files of given format include content of media attached to quiz.
+func IncludesMedia(format string) bool {
+	return writers[format].withMedia
+}
+
+// exportFileName will return name of the file quiz with given title is exported to, without its extension.
+// Only ASCII letters and digits of the title are kept, so that the name can be sent in headers as it is.
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+
+		return '-'
+	}, title)
+
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' }), "-")
+	if name == "" {
+		return "quiz"
+	}
+
+	return name
+}
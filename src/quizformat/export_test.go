@@ -0,0 +1,221 @@
+package quizformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// createExportQuiz will create a quiz containing a question of every type, the first one showing an image.
+func createExportQuiz(mediaID uuid.UUID) *models.Quiz {
+	trueValue, falseValue := true, false
+	answer := 3.14
+
+	quiz := &models.Quiz{
+		ID:              uuid.New(),
+		Title:           "Science: Basics/Intro",
+		MaxTime:         10,
+		NegativeMarking: 0.5,
+		Questions: []models.Question{
+			{
+				Text:        "Which of these is **red**?\nPick {one}.",
+				Type:        models.QuestionTypeSingleChoice,
+				Points:      2,
+				Explanation: "Mars is called the red planet.",
+				MediaIDs:    []uuid.UUID{mediaID},
+				Options: []models.Option{
+					{Answer: "Mars", IsCorrect: &trueValue, Feedback: "Right."},
+					{Answer: "Earth = blue", IsCorrect: &falseValue},
+				},
+			},
+			{
+				Text: "Which of these are planets?",
+				Type: models.QuestionTypeMultipleChoice,
+				Options: []models.Option{
+					{Answer: "Venus", IsCorrect: &trueValue},
+					{Answer: "Moon", IsCorrect: &falseValue},
+					{Answer: "Jupiter", IsCorrect: &trueValue},
+				},
+			},
+			{
+				Text: "The sun rises in the east.",
+				Type: models.QuestionTypeTrueFalse,
+				Options: []models.Option{
+					{Answer: "True", IsCorrect: &trueValue, Feedback: "Correct."},
+					{Answer: "False", IsCorrect: &falseValue, Feedback: "It rises in the east."},
+				},
+			},
+			{Text: "Value of pi?", Type: models.QuestionTypeNumeric, NumericAnswer: &answer, Tolerance: 0.01},
+			{Text: "Capital of France?", Type: models.QuestionTypeShortText, AcceptedAnswers: []string{"Paris", "paris"}},
+		},
+	}
+
+	for i := range quiz.Questions {
+		quiz.Questions[i].ID = uuid.New()
+		quiz.Questions[i].TextHTML = utils.RenderMarkdown(quiz.Questions[i].Text)
+	}
+
+	return quiz
+}
+
+// TestWriteGIFT will test that questions written to GIFT are read back with the same answers.
+func TestWriteGIFT(t *testing.T) {
+	quiz := createExportQuiz(uuid.New())
+
+	export, err := Write(FormatGIFT, quiz, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "science-basics-intro.gift.txt", export.FileName)
+	assert.Contains(t, string(export.Content), "$CATEGORY: $course$/top/Science: Basics-Intro\n")
+	assert.Contains(t, string(export.Content), `[markdown]Which of these is **red**?\nPick \{one\}.`)
+
+	imported, err := Read(FormatGIFT, bytes.NewReader(export.Content), "")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Empty(t, imported.Errors)
+	if !assert.Len(t, imported.Quizzes, 1) {
+		return
+	}
+
+	assert.Equal(t, "Science: Basics-Intro", imported.Quizzes[0].Quiz.Title)
+
+	questions := imported.Quizzes[0].Quiz.Questions
+	if !assert.Len(t, questions, len(quiz.Questions)) {
+		return
+	}
+
+	for i, question := range questions {
+		assert.Equal(t, quiz.Questions[i].Text, question.Text)
+		assert.Equal(t, quiz.Questions[i].Type, question.Type)
+		assert.Equal(t, quiz.Questions[i].Explanation, question.Explanation)
+		assert.Equal(t, quiz.Questions[i].AcceptedAnswers, question.AcceptedAnswers)
+		assert.Equal(t, quiz.Questions[i].NumericAnswer, question.NumericAnswer)
+		assert.Equal(t, quiz.Questions[i].Tolerance, question.Tolerance)
+		assert.Equal(t, quiz.Questions[i].Options, question.Options)
+	}
+}
+
+// TestWriteBundle will test that JSON bundles contain correct answers and content of media.
+func TestWriteBundle(t *testing.T) {
+	media := MediaFile{
+		Media:   models.Media{ID: uuid.New(), FileName: "mars.png", ContentType: "image/png"},
+		Content: []byte("image"),
+	}
+	quiz := createExportQuiz(media.ID)
+
+	export, err := Write(FormatJSON, quiz, []MediaFile{media})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "application/json", export.ContentType)
+
+	bundle := Bundle{}
+	err = json.Unmarshal(export.Content, &bundle)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "quiz-bundle", bundle.Format)
+	assert.Equal(t, BundleVersion, bundle.Version)
+	assert.Equal(t, quiz.ID, bundle.Quiz.ID)
+	assert.True(t, *bundle.Quiz.Questions[0].Options[0].IsCorrect)
+	assert.False(t, *bundle.Quiz.Questions[0].Options[1].IsCorrect)
+	assert.Equal(t, []MediaFile{media}, bundle.Media)
+}
+
+// TestWriteQTI will test that QTI packages contain a well-formed item for every question, the test, the manifest and media.
+func TestWriteQTI(t *testing.T) {
+	media := MediaFile{
+		Media:   models.Media{ID: uuid.New(), FileName: "mars.png", ContentType: "image/png"},
+		Content: []byte("image"),
+	}
+	quiz := createExportQuiz(media.ID)
+	quiz.Questions[1].TextHTML = `<p>Which of these <del>are</del> are <em>planets</em>?<br><input type="checkbox" disabled></p>`
+
+	export, err := Write(FormatQTI, quiz, []MediaFile{media})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "science-basics-intro.zip", export.FileName)
+
+	archive, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		content, err := file.Open()
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		data, err := io.ReadAll(content)
+		content.Close()
+		assert.Nil(t, err)
+		files[file.Name] = string(data)
+
+		if strings.HasSuffix(file.Name, ".xml") {
+			assert.Nil(t, checkWellFormed(data), file.Name)
+		}
+	}
+
+	assert.Len(t, files, len(quiz.Questions)+3)
+	assert.Equal(t, "image", files["media/"+media.ID.String()+".png"])
+	assert.Contains(t, files["imsmanifest.xml"], `href="media/`+media.ID.String()+`.png"`)
+	assert.Contains(t, files["assessment.xml"], `<timeLimits maxTime="600">`)
+
+	items := make([]string, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		items[i] = files["items/Q-"+question.ID.String()+".xml"]
+		assert.Contains(t, files["assessment.xml"], `href="items/Q-`+question.ID.String()+`.xml"`)
+	}
+
+	assert.Contains(t, items[0], `<strong>red</strong>`)
+	assert.Contains(t, items[0], `<img src="../media/`+media.ID.String()+`.png" alt="">`)
+	assert.Contains(t, items[0], `<correctResponse><value>O-1</value></correctResponse>`)
+	assert.Contains(t, items[0], `<baseValue baseType="float">-1</baseValue>`)
+	assert.Contains(t, items[1], `cardinality="multiple"`)
+	assert.Contains(t, items[1], `Which of these are are <em>planets</em>?<br></br></p>`)
+	assert.Contains(t, items[1], `<value>O-1</value><value>O-3</value>`)
+	assert.Contains(t, items[3], `<equal toleranceMode="absolute" tolerance="0.01 0.01">`)
+	assert.Contains(t, items[4], `<baseValue baseType="string">Paris</baseValue>`)
+}
+
+// TestWriteInvalidFormat will test that quizzes can not be exported to unknown formats.
+func TestWriteInvalidFormat(t *testing.T) {
+	_, err := Write(FormatCSV, createExportQuiz(uuid.New()), nil)
+	assert.Equal(t, "format must be one of qti, json or gift", err.Error())
+}
+
+// checkWellFormed will decode every token of an XML document, returning the first syntax error.
+func checkWellFormed(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
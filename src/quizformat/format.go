@@ -0,0 +1,169 @@
+package quizformat
+
+import (
+	"errors"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// Formats of files which quizzes can be imported from.
+const (
+	FormatCSV       = "csv"
+	FormatGIFT      = "gift"
+	FormatMoodleXML = "moodle"
+)
+
+// MaxImportSize is maximum size of an imported file in bytes.
+const MaxImportSize = 1 << 20
+
+// Import will contain quizzes read from an imported file, along with problems of rows or questions which could not be read.
+type Import struct {
+	Quizzes []ImportedQuiz
+	Errors  []models.ImportError
+}
+
+// ImportedQuiz will contain quiz read from an imported file along with the line where each of its questions starts.
+type ImportedQuiz struct {
+	Quiz  models.Quiz
+	Lines []int
+}
+
+// reader will read quizzes from content of a file. Questions are added to a quiz titled defaultTitle
+// unless the file specifies the quiz they belong to.
+type reader func(content io.Reader, defaultTitle string) (*Import, error)
+
+var readers = map[string]reader{
+	FormatCSV:       readCSV,
+	FormatGIFT:      readGIFT,
+	FormatMoodleXML: readMoodleXML,
+}
+
+// Read will read quizzes from content of a file of given format. An error is returned only if the file can not
+// be read at all, problems of single rows or questions are returned in Import.Errors.
+func Read(format string, content io.Reader, defaultTitle string) (*Import, error) {
+	read, ok := readers[format]
+	if !ok {
+		return nil, errors.New("format must be one of csv, gift or moodle")
+	}
+
+	// one more byte is read to know if the file is too large.
+	data, err := io.ReadAll(io.LimitReader(content, MaxImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxImportSize {
+		return nil, errors.New("file should not exceed 1 MB")
+	}
+
+	imported, err := read(strings.NewReader(strings.TrimPrefix(string(data), "\ufeff")), strings.TrimSpace(defaultTitle))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imported.Quizzes) == 0 && len(imported.Errors) == 0 {
+		return nil, errors.New("file does not contain any questions")
+	}
+
+	return imported, nil
+}
+
+// FormatFromFileName will guess format of a file from its extension, it returns empty string if it is unknown.
+func FormatFromFileName(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV
+	case ".gift", ".txt":
+		return FormatGIFT
+	case ".xml":
+		return FormatMoodleXML
+	default:
+		return ""
+	}
+}
+
+// importBuilder will group questions read from a file into quizzes by their title,
+// quizzes are kept in the order in which they first appear in the file.
+type importBuilder struct {
+	imported     *Import
+	quizIndex    map[string]int // lower-cased title to index of quiz
+	defaultTitle string
+}
+
+func newImportBuilder(defaultTitle string) *importBuilder {
+	return &importBuilder{
+		imported:     &Import{},
+		quizIndex:    map[string]int{},
+		defaultTitle: defaultTitle,
+	}
+}
+
+// add will append question starting at line to the quiz with given title.
+func (b *importBuilder) add(title string, line int, question models.Question) {
+	quiz := b.quiz(title)
+	quiz.Quiz.Questions = append(quiz.Quiz.Questions, question)
+	quiz.Lines = append(quiz.Lines, line)
+}
+
+// fail will report that row or question starting at line of the quiz with given title could not be read.
+func (b *importBuilder) fail(title string, line int, err error) {
+	b.imported.Errors = append(b.imported.Errors, models.ImportError{
+		Line:  line,
+		Quiz:  b.title(title),
+		Error: err.Error(),
+	})
+}
+
+// quiz will return quiz with given title, adding it if it does not exist.
+func (b *importBuilder) quiz(title string) *ImportedQuiz {
+	title = b.title(title)
+
+	i, ok := b.quizIndex[strings.ToLower(title)]
+	if !ok {
+		i = len(b.imported.Quizzes)
+		b.quizIndex[strings.ToLower(title)] = i
+		b.imported.Quizzes = append(b.imported.Quizzes, ImportedQuiz{Quiz: models.Quiz{Title: title}})
+	}
+
+	return &b.imported.Quizzes[i]
+}
+
+// title will return given title, or the default title if it is empty.
+func (b *importBuilder) title(title string) string {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return b.defaultTitle
+	}
+
+	return title
+}
+
+// categoryTitle will return name of the innermost category of a category path such as "$course$/top/Geography".
+func categoryTitle(category string) string {
+	category = strings.Trim(strings.TrimSpace(category), "/")
+	return strings.TrimSpace(category[strings.LastIndex(category, "/")+1:])
+}
+
+// htmlBreaks match elements which start a new line of text.
+var htmlBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+
+// blankLines match runs of empty lines left after removing elements.
+var blankLines = regexp.MustCompile(`\n\s*\n\s*(\n\s*)+`)
+
+// textPolicy removes every HTML element, keeping only their text.
+var textPolicy = bluemonday.StrictPolicy()
+
+// htmlToText will convert HTML of a question exported by another system to plain text, which is stored as Markdown.
+func htmlToText(source string) string {
+	text := htmlBreaks.ReplaceAllString(source, "\n")
+	text = html.UnescapeString(textPolicy.Sanitize(text))
+	text = blankLines.ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,520 @@
+package quizformat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// giftSpecialCharacters have to be escaped using backslash to be used as text in GIFT.
+const giftSpecialCharacters = `~=#{}:\`
+
+// giftAnswer is a single answer of a GIFT question, such as "~%50%Answer#Feedback".
+type giftAnswer struct {
+	marker   byte // '=' for correct answers and '~' for wrong ones
+	weight   float64
+	text     string
+	feedback string
+}
+
+// readGIFT will read quizzes from a file in GIFT format, where questions are separated by blank lines.
+// Questions following "$CATEGORY: name" are added to a quiz titled by the innermost category.
+// Multiple choice, true/false, short answer, missing word and numerical questions are supported.
+func readGIFT(content io.Reader, defaultTitle string) (*Import, error) {
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportSize)
+
+	builder := newImportBuilder(defaultTitle)
+	category := ""
+	var block []string
+	start, lineNumber := 0, 0
+
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+
+		question, err := parseGIFTQuestion(strings.Join(block, "\n"))
+		if err != nil {
+			builder.fail(category, start, err)
+		} else {
+			builder.add(category, start, *question)
+		}
+
+		block = nil
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "//"):
+			continue
+		case line == "":
+			flush()
+		case len(block) == 0 && strings.HasPrefix(line, "$CATEGORY:"):
+			category = categoryTitle(strings.TrimPrefix(line, "$CATEGORY:"))
+		default:
+			if len(block) == 0 {
+				start = lineNumber
+			}
+			block = append(block, line)
+		}
+	}
+
+	flush()
+
+	return builder.imported, scanner.Err()
+}
+
+// parseGIFTQuestion will map a single GIFT question onto a question.
+func parseGIFTQuestion(source string) (*models.Question, error) {
+	open := indexUnescaped(source, "{")
+	if open < 0 {
+		return nil, errors.New("answers of question must be enclosed in braces")
+	}
+
+	end := indexUnescaped(source[open+1:], "}")
+	if end < 0 {
+		return nil, errors.New("closing brace of answers is missing")
+	}
+	end += open + 1
+
+	text := strings.TrimSpace(source[:open])
+
+	// title of question is not used.
+	if strings.HasPrefix(text, "::") {
+		if i := strings.Index(text[2:], "::"); i >= 0 {
+			text = strings.TrimSpace(text[i+4:])
+		}
+	}
+
+	format := ""
+	if strings.HasPrefix(text, "[") {
+		if i := strings.Index(text, "]"); i > 0 {
+			format, text = strings.ToLower(text[1:i]), strings.TrimSpace(text[i+1:])
+		}
+	}
+
+	text = unescapeGIFT(text)
+
+	// text after answers makes it a missing word question, the answers fill the blank.
+	if after := strings.TrimSpace(source[end+1:]); after != "" {
+		text += " _____ " + unescapeGIFT(after)
+	}
+
+	if format == "html" {
+		text = htmlToText(text)
+	}
+
+	question := &models.Question{Text: text}
+
+	err := parseGIFTAnswers(question, strings.TrimSpace(source[open+1:end]))
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// parseGIFTAnswers will set type, options and answers of question from answers enclosed in braces.
+func parseGIFTAnswers(question *models.Question, answers string) error {
+	if i := indexUnescaped(answers, "####"); i >= 0 {
+		question.Explanation = unescapeGIFT(strings.TrimSpace(answers[i+4:]))
+		answers = strings.TrimSpace(answers[:i])
+	}
+
+	if answers == "" {
+		return errors.New("essay questions are not supported")
+	}
+
+	if strings.HasPrefix(answers, "#") {
+		return parseGIFTNumeric(question, answers[1:])
+	}
+
+	feedback := splitUnescaped(answers, "#")
+	switch strings.ToUpper(strings.TrimSpace(feedback[0])) {
+	case "T", "TRUE", "F", "FALSE":
+		isTrue := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(feedback[0])), "T")
+		question.Type = models.QuestionTypeTrueFalse
+		question.Options = trueFalseOptions(isTrue)
+
+		// first feedback is shown for the wrong answer and second one for the correct answer.
+		correct, wrong := 0, 1
+		if !isTrue {
+			correct, wrong = 1, 0
+		}
+
+		if len(feedback) > 1 {
+			question.Options[wrong].Feedback = unescapeGIFT(strings.TrimSpace(feedback[1]))
+		}
+
+		if len(feedback) > 2 {
+			question.Options[correct].Feedback = unescapeGIFT(strings.TrimSpace(feedback[2]))
+		}
+
+		return nil
+	}
+
+	parsed, err := parseGIFTChoices(answers)
+	if err != nil {
+		return err
+	}
+
+	onlyCorrect, correctCount := true, 0
+	for _, answer := range parsed {
+		if answer.marker == '~' {
+			onlyCorrect = false
+		}
+
+		if answer.marker == '=' || answer.weight > 0 {
+			correctCount++
+		}
+	}
+
+	// answers which are all correct are accepted answers of a short answer question.
+	if onlyCorrect {
+		question.Type = models.QuestionTypeShortText
+		for _, answer := range parsed {
+			question.AcceptedAnswers = append(question.AcceptedAnswers, answer.text)
+		}
+
+		return nil
+	}
+
+	question.Type = models.QuestionTypeSingleChoice
+	if correctCount > 1 {
+		question.Type = models.QuestionTypeMultipleChoice
+	}
+
+	for _, answer := range parsed {
+		isCorrect := answer.marker == '=' || answer.weight > 0
+		question.Options = append(question.Options, models.Option{
+			Answer:    answer.text,
+			IsCorrect: &isCorrect,
+			Feedback:  answer.feedback,
+		})
+	}
+
+	return nil
+}
+
+// parseGIFTChoices will split answers into answers starting with "=" or "~".
+func parseGIFTChoices(answers string) ([]giftAnswer, error) {
+	var parsed []giftAnswer
+
+	for _, source := range splitGIFTAnswers(answers) {
+		answer := giftAnswer{marker: source[0]}
+		source = source[1:]
+
+		if strings.HasPrefix(source, "%") {
+			i := strings.Index(source[1:], "%")
+			if i < 0 {
+				return nil, errors.New("weight of answer must be enclosed in percent signs")
+			}
+
+			weight, err := strconv.ParseFloat(source[1:i+1], 64)
+			if err != nil {
+				return nil, errors.New("weight of answer must be a number")
+			}
+
+			answer.weight, source = weight, source[i+2:]
+		}
+
+		if indexUnescaped(source, "->") >= 0 {
+			return nil, errors.New("matching questions are not supported")
+		}
+
+		parts := splitUnescaped(source, "#")
+		answer.text = unescapeGIFT(strings.TrimSpace(parts[0]))
+		if len(parts) > 1 {
+			answer.feedback = unescapeGIFT(strings.TrimSpace(strings.Join(parts[1:], "#")))
+		}
+
+		parsed = append(parsed, answer)
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("answers must start with = or ~")
+	}
+
+	return parsed, nil
+}
+
+// parseGIFTNumeric will set answer of a numerical question, written as "answer", "answer:tolerance" or "min..max".
+// Only the first answer is used when the question has multiple answers.
+func parseGIFTNumeric(question *models.Question, answers string) error {
+	question.Type = models.QuestionTypeNumeric
+
+	if answers = strings.TrimSpace(answers); strings.HasPrefix(answers, "=") {
+		answers = splitGIFTAnswers(answers)[0][1:]
+	}
+
+	answer := strings.TrimSpace(splitUnescaped(answers, "#")[0])
+	if strings.HasPrefix(answer, "%") {
+		if i := strings.Index(answer[1:], "%"); i >= 0 {
+			answer = answer[i+2:]
+		}
+	}
+
+	var tolerance float64
+	var err error
+
+	if minimum, maximum, ok := strings.Cut(answer, ".."); ok {
+		low, lowErr := strconv.ParseFloat(strings.TrimSpace(minimum), 64)
+		high, highErr := strconv.ParseFloat(strings.TrimSpace(maximum), 64)
+		if lowErr != nil || highErr != nil || low > high {
+			return errors.New("range of numerical answer is invalid")
+		}
+
+		middle := (low + high) / 2
+		question.NumericAnswer = &middle
+		question.Tolerance = (high - low) / 2
+
+		return nil
+	}
+
+	answer, toleranceText, hasTolerance := strings.Cut(answer, ":")
+	if hasTolerance {
+		tolerance, err = strconv.ParseFloat(strings.TrimSpace(toleranceText), 64)
+		if err != nil {
+			return errors.New("tolerance of numerical answer must be a number")
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	if err != nil {
+		return errors.New("numerical answer must be a number")
+	}
+
+	question.NumericAnswer = &value
+	question.Tolerance = tolerance
+
+	return nil
+}
+
+// splitGIFTAnswers will split answers at every unescaped "=" or "~", each part starts with its marker.
+func splitGIFTAnswers(answers string) []string {
+	var parts []string
+	start := -1
+
+	for i := 0; i < len(answers); i++ {
+		switch answers[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if start >= 0 {
+				parts = append(parts, answers[start:i])
+			}
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		parts = append(parts, answers[start:])
+	}
+
+	return parts
+}
+
+// indexUnescaped will return index of the first occurrence of substr in s which is not escaped by backslash.
+func indexUnescaped(s, substr string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitUnescaped will split s at every occurrence of sep which is not escaped by backslash.
+func splitUnescaped(s, sep string) []string {
+	var parts []string
+
+	for {
+		i := indexUnescaped(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+
+		parts = append(parts, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+// unescapeGIFT will replace escaped special characters with the characters, and "\n" with a new line.
+func unescapeGIFT(s string) string {
+	var unescaped strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch {
+			case s[i+1] == 'n':
+				unescaped.WriteByte('\n')
+				i++
+				continue
+			case strings.IndexByte(giftSpecialCharacters, s[i+1]) >= 0:
+				unescaped.WriteByte(s[i+1])
+				i++
+				continue
+			}
+		}
+
+		unescaped.WriteByte(s[i])
+	}
+
+	return unescaped.String()
+}
+
+// writeGIFT will write questions of quiz to GIFT under a category named by title of quiz. Text is written in
+// Markdown format. GIFT can not contain points, pools or media of questions, so they are left out.
+func writeGIFT(quiz *models.Quiz, _ []MediaFile) ([]byte, error) {
+	var gift strings.Builder
+
+	fmt.Fprintf(&gift, "$CATEGORY: $course$/top/%s\n\n", strings.ReplaceAll(quiz.Title, "/", "-"))
+
+	for i, question := range quiz.Questions {
+		fmt.Fprintf(&gift, "::Q%d:: [markdown]%s {\n", i+1, escapeGIFT(question.Text))
+
+		for _, answer := range giftAnswers(&question) {
+			gift.WriteString(answer + "\n")
+		}
+
+		if question.Explanation != "" {
+			gift.WriteString("####" + escapeGIFT(question.Explanation) + "\n")
+		}
+
+		gift.WriteString("}\n\n")
+	}
+
+	return []byte(gift.String()), nil
+}
+
+// giftAnswers will return lines of answers of question written between its braces.
+func giftAnswers(question *models.Question) []string {
+	switch question.Type {
+	case models.QuestionTypeShortText:
+		var answers []string
+		for _, answer := range question.AcceptedAnswers {
+			answers = append(answers, "="+escapeGIFT(answer))
+		}
+
+		return answers
+	case models.QuestionTypeNumeric:
+		answer := "#"
+		if question.NumericAnswer != nil {
+			answer += formatGIFTNumber(*question.NumericAnswer)
+		}
+
+		if question.Tolerance > 0 {
+			answer += ":" + formatGIFTNumber(question.Tolerance)
+		}
+
+		return []string{answer}
+	case models.QuestionTypeTrueFalse:
+		if answer, ok := giftTrueFalse(question); ok {
+			return []string{answer}
+		}
+	}
+
+	correctCount := 0
+	for _, option := range question.Options {
+		if option.IsCorrect != nil && *option.IsCorrect {
+			correctCount++
+		}
+	}
+
+	var answers []string
+	for _, option := range question.Options {
+		isCorrect := option.IsCorrect != nil && *option.IsCorrect
+
+		// every correct answer of a multiple choice question is awarded an equal share of points.
+		answer := "~"
+		switch {
+		case question.Type == models.QuestionTypeMultipleChoice && isCorrect:
+			answer = "~%" + formatGIFTNumber(100/float64(correctCount)) + "%"
+		case question.Type == models.QuestionTypeMultipleChoice:
+			answer = "~%-100%"
+		case isCorrect:
+			answer = "="
+		}
+
+		answer += escapeGIFT(option.Answer)
+		if option.Feedback != "" {
+			answer += "#" + escapeGIFT(option.Feedback)
+		}
+
+		answers = append(answers, answer)
+	}
+
+	return answers
+}
+
+// giftTrueFalse will return answer of a true/false question whose options are "True" and "False", as "T" or "F"
+// followed by feedback for the wrong and the correct answer. Questions with other options are written as
+// multiple choice questions.
+func giftTrueFalse(question *models.Question) (string, bool) {
+	var trueOption, falseOption *models.Option
+
+	for i := range question.Options {
+		switch strings.ToLower(strings.TrimSpace(question.Options[i].Answer)) {
+		case "true":
+			trueOption = &question.Options[i]
+		case "false":
+			falseOption = &question.Options[i]
+		}
+	}
+
+	if len(question.Options) != 2 || trueOption == nil || falseOption == nil {
+		return "", false
+	}
+
+	answer, correct, wrong := "T", trueOption, falseOption
+	if trueOption.IsCorrect == nil || !*trueOption.IsCorrect {
+		answer, correct, wrong = "F", falseOption, trueOption
+	}
+
+	if correct.Feedback != "" || wrong.Feedback != "" {
+		answer += "#" + escapeGIFT(wrong.Feedback) + "#" + escapeGIFT(correct.Feedback)
+	}
+
+	return answer, true
+}
+
+// formatGIFTNumber will format number with at most five decimals.
+func formatGIFTNumber(number float64) string {
+	return strconv.FormatFloat(math.Round(number*1e5)/1e5, 'f', -1, 64)
+}
+
+// escapeGIFT will escape special characters of s using backslash, and write new lines as "\n".
+func escapeGIFT(s string) string {
+	var escaped strings.Builder
+
+	for _, r := range strings.ReplaceAll(s, "\r\n", "\n") {
+		switch {
+		case r == '\n':
+			escaped.WriteString(`\n`)
+		case r == '\r':
+		case strings.ContainsRune(giftSpecialCharacters, r):
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,100 @@
+package quizformat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadGIFT will test that every supported type of GIFT question is mapped onto a question.
+func TestReadGIFT(t *testing.T) {
+	content := `// questions of the default quiz
+::Q1:: What is the capital of France? {
+	=Paris#Correct!
+	~London#No, that is in England.
+	####Paris has been the capital since 987.
+}
+
+$CATEGORY: $course$/top/Science
+
+The sun rises in the east.{T#It rises in the east.#Right.}
+
+Which of these are planets? {~%50%Mars ~%50%Venus ~%-100%Moon}
+
+Value of pi? {#3.14:0.01}
+
+Water boils at {#95..105} degrees.
+
+Two plus two equals {=four =4}.
+
+Escaped \{braces\} and 1\=1? {=yes ~no}
+`
+
+	imported, err := Read(FormatGIFT, strings.NewReader(content), "General")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Empty(t, imported.Errors)
+	assert.Len(t, imported.Quizzes, 2)
+
+	general := imported.Quizzes[0]
+	assert.Equal(t, "General", general.Quiz.Title)
+	assert.Equal(t, []int{2}, general.Lines)
+
+	question := general.Quiz.Questions[0]
+	assert.Equal(t, "What is the capital of France?", question.Text)
+	assert.Equal(t, models.QuestionTypeSingleChoice, question.Type)
+	assert.Equal(t, "Paris has been the capital since 987.", question.Explanation)
+	assert.Equal(t, "Paris", question.Options[0].Answer)
+	assert.True(t, *question.Options[0].IsCorrect)
+	assert.Equal(t, "No, that is in England.", question.Options[1].Feedback)
+
+	science := imported.Quizzes[1]
+	assert.Equal(t, "Science", science.Quiz.Title)
+	assert.Len(t, science.Quiz.Questions, 6)
+
+	trueFalse := science.Quiz.Questions[0]
+	assert.Equal(t, models.QuestionTypeTrueFalse, trueFalse.Type)
+	assert.True(t, *trueFalse.Options[0].IsCorrect)
+	assert.Equal(t, "Right.", trueFalse.Options[0].Feedback)
+	assert.Equal(t, "It rises in the east.", trueFalse.Options[1].Feedback)
+
+	multipleChoice := science.Quiz.Questions[1]
+	assert.Equal(t, models.QuestionTypeMultipleChoice, multipleChoice.Type)
+	assert.True(t, *multipleChoice.Options[1].IsCorrect)
+	assert.False(t, *multipleChoice.Options[2].IsCorrect)
+
+	assert.Equal(t, 3.14, *science.Quiz.Questions[2].NumericAnswer)
+	assert.Equal(t, 0.01, science.Quiz.Questions[2].Tolerance)
+
+	missingWord := science.Quiz.Questions[3]
+	assert.Equal(t, "Water boils at _____ degrees.", missingWord.Text)
+	assert.Equal(t, 100.0, *missingWord.NumericAnswer)
+	assert.Equal(t, 5.0, missingWord.Tolerance)
+
+	assert.Equal(t, models.QuestionTypeShortText, science.Quiz.Questions[4].Type)
+	assert.Equal(t, []string{"four", "4"}, science.Quiz.Questions[4].AcceptedAnswers)
+
+	assert.Equal(t, "Escaped {braces} and 1=1?", science.Quiz.Questions[5].Text)
+}
+
+// TestReadGIFTErrors will test that unsupported and malformed questions are reported along with their line.
+func TestReadGIFTErrors(t *testing.T) {
+	content := "Describe your day. {}\n\nMatch the capitals. {=France -> Paris =India -> Delhi}\n\nNo answers\n\n" +
+		"What is the capital of France? {=Paris ~London}\n"
+
+	imported, err := Read(FormatGIFT, strings.NewReader(content), "General")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, []models.ImportError{
+		{Line: 1, Quiz: "General", Error: "essay questions are not supported"},
+		{Line: 3, Quiz: "General", Error: "matching questions are not supported"},
+		{Line: 5, Quiz: "General", Error: "answers of question must be enclosed in braces"},
+	}, imported.Errors)
+	assert.Len(t, imported.Quizzes[0].Quiz.Questions, 1)
+}
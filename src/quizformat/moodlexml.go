@@ -0,0 +1,185 @@
+package quizformat
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// moodleText is an element of Moodle XML containing text in given format, such as questiontext or feedback.
+type moodleText struct {
+	Format string `xml:"format,attr"`
+	Text   string `xml:"text"`
+}
+
+// moodleAnswer is an answer of a Moodle XML question, fraction is the percentage of points it is awarded.
+type moodleAnswer struct {
+	Fraction  string     `xml:"fraction,attr"`
+	Format    string     `xml:"format,attr"`
+	Text      string     `xml:"text"`
+	Feedback  moodleText `xml:"feedback"`
+	Tolerance string     `xml:"tolerance"`
+}
+
+// moodleQuestion is a question element of Moodle XML, category elements only contain Category.
+type moodleQuestion struct {
+	Type            string         `xml:"type,attr"`
+	Category        moodleText     `xml:"category"`
+	QuestionText    moodleText     `xml:"questiontext"`
+	GeneralFeedback moodleText     `xml:"generalfeedback"`
+	DefaultGrade    string         `xml:"defaultgrade"`
+	Single          string         `xml:"single"`
+	Answers         []moodleAnswer `xml:"answer"`
+}
+
+// readMoodleXML will read quizzes from a file in Moodle XML format. Questions following a category are added
+// to a quiz titled by the innermost category. Multichoice, truefalse, shortanswer and numerical questions are
+// supported, descriptions are skipped. Text in HTML format is converted to plain text.
+func readMoodleXML(content io.Reader, defaultTitle string) (*Import, error) {
+	decoder := xml.NewDecoder(content)
+	builder := newImportBuilder(defaultTitle)
+	category := ""
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid xml: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "question" {
+			continue
+		}
+
+		line, _ := decoder.InputPos()
+		source := moodleQuestion{}
+
+		err = decoder.DecodeElement(&source, &start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xml: %w", err)
+		}
+
+		switch source.Type {
+		case "category":
+			category = categoryTitle(source.Category.Text)
+			continue
+		case "description":
+			continue
+		}
+
+		question, err := source.question()
+		if err != nil {
+			builder.fail(category, line, err)
+			continue
+		}
+
+		builder.add(category, line, *question)
+	}
+
+	return builder.imported, nil
+}
+
+// question will map Moodle XML question onto a question.
+func (source *moodleQuestion) question() (*models.Question, error) {
+	question := &models.Question{
+		Text:        source.QuestionText.text(),
+		Explanation: source.GeneralFeedback.text(),
+	}
+
+	if grade := strings.TrimSpace(source.DefaultGrade); grade != "" {
+		points, err := strconv.ParseFloat(grade, 64)
+		if err != nil {
+			return nil, errors.New("default grade must be a number")
+		}
+		question.Points = points
+	}
+
+	switch source.Type {
+	case "multichoice", "truefalse":
+		question.Type = models.QuestionTypeMultipleChoice
+		if source.Type == "truefalse" {
+			question.Type = models.QuestionTypeTrueFalse
+		} else if source.Single == "true" || source.Single == "1" {
+			question.Type = models.QuestionTypeSingleChoice
+		}
+
+		for _, answer := range source.Answers {
+			isCorrect := answer.fraction() > 0
+			text := (&moodleText{Format: answer.Format, Text: answer.Text}).text()
+
+			// answers of truefalse questions are "true" and "false".
+			if source.Type == "truefalse" && len(text) > 0 {
+				text = strings.ToUpper(text[:1]) + text[1:]
+			}
+
+			question.Options = append(question.Options, models.Option{
+				Answer:    text,
+				IsCorrect: &isCorrect,
+				Feedback:  answer.Feedback.text(),
+			})
+		}
+	case "shortanswer":
+		question.Type = models.QuestionTypeShortText
+
+		for _, answer := range source.Answers {
+			if answer.fraction() >= 100 {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, strings.TrimSpace(answer.Text))
+			}
+		}
+	case "numerical":
+		question.Type = models.QuestionTypeNumeric
+
+		for _, answer := range source.Answers {
+			if answer.fraction() < 100 {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimSpace(answer.Text), 64)
+			if err != nil {
+				return nil, errors.New("numerical answer must be a number")
+			}
+
+			if tolerance := strings.TrimSpace(answer.Tolerance); tolerance != "" {
+				question.Tolerance, err = strconv.ParseFloat(tolerance, 64)
+				if err != nil {
+					return nil, errors.New("tolerance of numerical answer must be a number")
+				}
+			}
+
+			question.NumericAnswer = &value
+			break
+		}
+	default:
+		return nil, fmt.Errorf("question type %s is not supported", source.Type)
+	}
+
+	return question, nil
+}
+
+// fraction will return percentage of points awarded for the answer, invalid fractions award no points.
+func (answer *moodleAnswer) fraction() float64 {
+	fraction, err := strconv.ParseFloat(strings.TrimSpace(answer.Fraction), 64)
+	if err != nil {
+		return 0
+	}
+
+	return fraction
+}
+
+// text will return text of element, converting it to plain text if it is HTML.
+func (t *moodleText) text() string {
+	if t.Format == "" || t.Format == "html" {
+		return htmlToText(t.Text)
+	}
+
+	return strings.TrimSpace(t.Text)
+}
@@ -0,0 +1,81 @@
+package quizformat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadMoodleXML will test that questions of Moodle XML are mapped onto questions of quizzes named by their category.
+func TestReadMoodleXML(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<quiz>
+  <question type="category">
+    <category><text>$course$/top/Geography</text></category>
+  </question>
+  <question type="multichoice">
+    <name><text>Capital</text></name>
+    <questiontext format="html"><text><![CDATA[<p>What is the <b>capital</b> of France?</p>]]></text></questiontext>
+    <generalfeedback format="html"><text>Paris &amp; nothing else.</text></generalfeedback>
+    <defaultgrade>2.0000000</defaultgrade>
+    <single>true</single>
+    <answer fraction="100" format="html"><text>Paris</text><feedback format="html"><text>Correct</text></feedback></answer>
+    <answer fraction="0" format="html"><text>London</text></answer>
+  </question>
+  <question type="description">
+    <questiontext format="html"><text>Answer the following questions.</text></questiontext>
+  </question>
+  <question type="truefalse">
+    <questiontext format="moodle_auto_format"><text>The Nile is in Africa.</text></questiontext>
+    <answer fraction="100"><text>true</text></answer>
+    <answer fraction="0"><text>false</text></answer>
+  </question>
+  <question type="shortanswer">
+    <questiontext format="plain_text"><text>Capital of Japan?</text></questiontext>
+    <answer fraction="100"><text>Tokyo</text></answer>
+    <answer fraction="50"><text>Kyoto</text></answer>
+  </question>
+  <question type="numerical">
+    <questiontext format="plain_text"><text>Height of Everest in km?</text></questiontext>
+    <answer fraction="100"><text>8.8</text><tolerance>0.1</tolerance></answer>
+  </question>
+  <question type="essay">
+    <questiontext format="plain_text"><text>Describe your country.</text></questiontext>
+  </question>
+</quiz>
+`
+
+	imported, err := Read(FormatMoodleXML, strings.NewReader(content), "General")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Len(t, imported.Quizzes, 1)
+	assert.Equal(t, []models.ImportError{
+		{Line: 32, Quiz: "Geography", Error: "question type essay is not supported"},
+	}, imported.Errors)
+
+	quiz := imported.Quizzes[0].Quiz
+	assert.Equal(t, "Geography", quiz.Title)
+	assert.Len(t, quiz.Questions, 4)
+
+	assert.Equal(t, "What is the capital of France?", quiz.Questions[0].Text)
+	assert.Equal(t, "Paris & nothing else.", quiz.Questions[0].Explanation)
+	assert.Equal(t, models.QuestionTypeSingleChoice, quiz.Questions[0].Type)
+	assert.Equal(t, 2.0, quiz.Questions[0].Points)
+	assert.True(t, *quiz.Questions[0].Options[0].IsCorrect)
+	assert.Equal(t, "Correct", quiz.Questions[0].Options[0].Feedback)
+
+	assert.Equal(t, models.QuestionTypeTrueFalse, quiz.Questions[1].Type)
+	assert.Equal(t, "True", quiz.Questions[1].Options[0].Answer)
+
+	assert.Equal(t, []string{"Tokyo"}, quiz.Questions[2].AcceptedAnswers)
+
+	assert.Equal(t, 8.8, *quiz.Questions[3].NumericAnswer)
+	assert.Equal(t, 0.1, quiz.Questions[3].Tolerance)
+
+	_, err = Read(FormatMoodleXML, strings.NewReader("<quiz><question>"), "General")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,580 @@
+package quizformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/utils"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Namespaces and schemas of files in a QTI 2.1 package.
+const (
+	qtiNamespace      = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiSchemaLocation = qtiNamespace + " http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"
+	cpNamespace       = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	xsiNamespace      = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// qtiTestFile is path of the assessment test in a QTI package, items are stored under qtiItemDir.
+const (
+	qtiTestFile = "assessment.xml"
+	qtiItemDir  = "items/"
+	qtiMediaDir = "media/"
+	qtiResponse = "RESPONSE"
+	qtiScore    = "SCORE"
+	qtiMaxScore = "MAXSCORE"
+)
+
+// qtiMediaExtensions are extensions of media files in a QTI package by their content type.
+var qtiMediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// qtiElements are XHTML elements which can be used in content of QTI items, other elements are replaced by their content.
+var qtiElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "caption": true, "cite": true,
+	"code": true, "dd": true, "div": true, "dl": true, "dt": true, "em": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "hr": true, "i": true, "img": true, "kbd": true,
+	"li": true, "ol": true, "p": true, "pre": true, "q": true, "samp": true, "small": true, "span": true,
+	"strong": true, "sub": true, "sup": true, "table": true, "tbody": true, "td": true, "tfoot": true,
+	"th": true, "thead": true, "tr": true, "ul": true, "var": true,
+}
+
+// qtiAttributes are attributes of XHTML elements kept in content of QTI items.
+var qtiAttributes = map[string]bool{"href": true, "src": true, "alt": true, "class": true}
+
+// writeQTI will write quiz to a zip package of IMS QTI 2.1, containing an assessment item for every question,
+// an assessment test referring to them and the media they show. Questions of a pool are put in a section
+// which selects as many of them as the pool draws.
+func writeQTI(quiz *models.Quiz, media []MediaFile) ([]byte, error) {
+	files := map[uuid.UUID]string{} // media ID to path of its file in the package
+	for _, file := range media {
+		files[file.ID] = qtiMediaDir + file.ID.String() + qtiMediaExtensions[file.ContentType]
+	}
+
+	var content bytes.Buffer
+	archive := zip.NewWriter(&content)
+
+	add := func(name string, data []byte) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(data)
+		return err
+	}
+
+	var resources []qtiResource
+
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+
+		item, err := qtiItem(quiz, question, i, files)
+		if err != nil {
+			return nil, err
+		}
+
+		resource := qtiResource{
+			identifier: qtiItemIdentifier(question),
+			kind:       "imsqti_item_xmlv2p1",
+			href:       qtiItemDir + qtiItemIdentifier(question) + ".xml",
+		}
+
+		resource.files = append(resource.files, resource.href)
+		for _, id := range (&models.Quiz{Questions: quiz.Questions[i : i+1]}).MediaIDs() {
+			if path, ok := files[id]; ok {
+				resource.files = append(resource.files, path)
+			}
+		}
+
+		err = add(resource.href, item)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, resource)
+	}
+
+	test, err := qtiTest(quiz)
+	if err != nil {
+		return nil, err
+	}
+
+	err = add(qtiTestFile, test)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := qtiManifest(quiz, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = add("imsmanifest.xml", manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range media {
+		err = add(files[file.ID], file.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return content.Bytes(), nil
+}
+
+// qtiResource is a resource listed in manifest of a QTI package.
+type qtiResource struct {
+	identifier string
+	kind       string
+	href       string
+	files      []string
+}
+
+// qtiItem will write question to an assessment item. Choice questions use a choice interaction, short text and
+// numeric questions use a text entry interaction. Points of question are awarded for a correct response, and
+// deducted according to negative marking of quiz for a wrong one.
+func qtiItem(quiz *models.Quiz, question *models.Question, index int, files map[uuid.UUID]string) ([]byte, error) {
+	w := newXMLWriter()
+
+	w.start("assessmentItem", "xmlns", qtiNamespace, "xmlns:xsi", xsiNamespace,
+		"xsi:schemaLocation", qtiSchemaLocation, "identifier", qtiItemIdentifier(question),
+		"title", fmt.Sprintf("Question %d", index+1), "adaptive", "false", "timeDependent", "false")
+
+	cardinality, baseType := "single", "identifier"
+	var correct []string
+
+	switch question.Type {
+	case models.QuestionTypeShortText:
+		baseType = "string"
+		if len(question.AcceptedAnswers) > 0 {
+			correct = question.AcceptedAnswers[:1]
+		}
+	case models.QuestionTypeNumeric:
+		baseType = "float"
+		if question.NumericAnswer != nil {
+			correct = []string{formatQTINumber(*question.NumericAnswer)}
+		}
+	default:
+		if question.Type == models.QuestionTypeMultipleChoice {
+			cardinality = "multiple"
+		}
+
+		for i, option := range question.Options {
+			if option.IsCorrect != nil && *option.IsCorrect {
+				correct = append(correct, qtiChoiceIdentifier(i))
+			}
+		}
+	}
+
+	w.start("responseDeclaration", "identifier", qtiResponse, "cardinality", cardinality, "baseType", baseType)
+	w.start("correctResponse")
+	for _, value := range correct {
+		w.element("value", value)
+	}
+	w.end("correctResponse")
+	w.end("responseDeclaration")
+
+	qtiOutcomeDeclaration(w, qtiScore, "0")
+	qtiOutcomeDeclaration(w, qtiMaxScore, formatQTINumber(question.Points))
+
+	w.start("itemBody")
+	w.start("div")
+	writeXHTML(w, renderedHTML(question.TextHTML, question.Text))
+	w.end("div")
+	qtiImages(w, question.MediaIDs, files, "p")
+
+	switch question.Type {
+	case models.QuestionTypeShortText, models.QuestionTypeNumeric:
+		w.start("p")
+		w.start("textEntryInteraction", "responseIdentifier", qtiResponse)
+		w.end("textEntryInteraction")
+		w.end("p")
+	default:
+		maxChoices := "1"
+		if question.Type == models.QuestionTypeMultipleChoice {
+			maxChoices = "0"
+		}
+
+		w.start("choiceInteraction", "responseIdentifier", qtiResponse,
+			"shuffle", strconv.FormatBool(quiz.ShuffleOptions), "maxChoices", maxChoices)
+		for i, option := range question.Options {
+			w.start("simpleChoice", "identifier", qtiChoiceIdentifier(i))
+			writeXHTML(w, renderedHTML(option.AnswerHTML, option.Answer))
+			qtiImages(w, option.MediaIDs, files, "")
+			w.end("simpleChoice")
+		}
+		w.end("choiceInteraction")
+	}
+
+	w.end("itemBody")
+
+	qtiResponseProcessing(w, quiz, question)
+
+	w.end("assessmentItem")
+
+	return w.bytes()
+}
+
+// qtiResponseProcessing will write rules setting score of an item from the response.
+func qtiResponseProcessing(w *xmlWriter, quiz *models.Quiz, question *models.Question) {
+	w.start("responseProcessing")
+	w.start("responseCondition")
+	w.start("responseIf")
+
+	switch question.Type {
+	case models.QuestionTypeShortText:
+		w.start("or")
+		for _, answer := range question.AcceptedAnswers {
+			w.start("stringMatch", "caseSensitive", "false")
+			w.start("variable", "identifier", qtiResponse)
+			w.end("variable")
+			w.element("baseValue", answer, "baseType", "string")
+			w.end("stringMatch")
+		}
+		w.end("or")
+	case models.QuestionTypeNumeric:
+		if question.Tolerance > 0 {
+			tolerance := formatQTINumber(question.Tolerance)
+			w.start("equal", "toleranceMode", "absolute", "tolerance", tolerance+" "+tolerance)
+		} else {
+			w.start("equal", "toleranceMode", "exact")
+		}
+		qtiResponseVariables(w)
+		w.end("equal")
+	default:
+		w.start("match")
+		qtiResponseVariables(w)
+		w.end("match")
+	}
+
+	qtiSetScore(w, question.Points)
+	w.end("responseIf")
+
+	if quiz.NegativeMarking > 0 {
+		w.start("responseElseIf")
+		w.start("not")
+		w.start("isNull")
+		w.start("variable", "identifier", qtiResponse)
+		w.end("variable")
+		w.end("isNull")
+		w.end("not")
+		qtiSetScore(w, -question.Points*quiz.NegativeMarking)
+		w.end("responseElseIf")
+	}
+
+	w.end("responseCondition")
+	w.end("responseProcessing")
+}
+
+// qtiResponseVariables will write the response and the correct response, which are compared by an operator.
+func qtiResponseVariables(w *xmlWriter) {
+	w.start("variable", "identifier", qtiResponse)
+	w.end("variable")
+	w.start("correct", "identifier", qtiResponse)
+	w.end("correct")
+}
+
+// qtiSetScore will write a rule setting score of an item to points.
+func qtiSetScore(w *xmlWriter, points float64) {
+	w.start("setOutcomeValue", "identifier", qtiScore)
+	w.element("baseValue", formatQTINumber(points), "baseType", "float")
+	w.end("setOutcomeValue")
+}
+
+// qtiOutcomeDeclaration will write declaration of a float outcome with given default value.
+func qtiOutcomeDeclaration(w *xmlWriter, identifier, defaultValue string) {
+	w.start("outcomeDeclaration", "identifier", identifier, "cardinality", "single", "baseType", "float")
+	w.start("defaultValue")
+	w.element("value", defaultValue)
+	w.end("defaultValue")
+	w.end("outcomeDeclaration")
+}
+
+// qtiImages will write an image for every media which is included in the package, wrapped in element if it is not empty.
+// Items are stored in their own directory, so images refer to media relative to it.
+func qtiImages(w *xmlWriter, mediaIDs []uuid.UUID, files map[uuid.UUID]string, element string) {
+	var paths []string
+	for _, id := range mediaIDs {
+		if path, ok := files[id]; ok {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return
+	}
+
+	if element != "" {
+		w.start(element)
+	}
+
+	for _, path := range paths {
+		w.start("img", "src", "../"+path, "alt", "")
+		w.end("img")
+	}
+
+	if element != "" {
+		w.end(element)
+	}
+}
+
+// qtiTest will write the assessment test of quiz. Questions without a pool are in the main section, which contains
+// a hidden section for every pool.
+func qtiTest(quiz *models.Quiz) ([]byte, error) {
+	w := newXMLWriter()
+
+	w.start("assessmentTest", "xmlns", qtiNamespace, "xmlns:xsi", xsiNamespace,
+		"xsi:schemaLocation", qtiSchemaLocation, "identifier", "T-"+quiz.ID.String(), "title", quiz.Title)
+
+	qtiOutcomeDeclaration(w, qtiScore, "0")
+
+	if quiz.MaxTime > 0 {
+		w.start("timeLimits", "maxTime", strconv.FormatUint(quiz.MaxTime*60, 10))
+		w.end("timeLimits")
+	}
+
+	w.start("testPart", "identifier", "P-1", "navigationMode", "nonlinear", "submissionMode", "simultaneous")
+	w.start("assessmentSection", "identifier", "S-main", "title", quiz.Title, "visible", "true")
+
+	if quiz.ShuffleQuestions {
+		w.start("ordering", "shuffle", "true")
+		w.end("ordering")
+	}
+
+	qtiItemRefs(w, quiz, "")
+
+	for i, pool := range quiz.Pools {
+		w.start("assessmentSection", "identifier", fmt.Sprintf("S-pool-%d", i+1), "title", pool.Name, "visible", "false")
+		w.start("selection", "select", strconv.Itoa(pool.DrawCount))
+		w.end("selection")
+		qtiItemRefs(w, quiz, pool.Name)
+		w.end("assessmentSection")
+	}
+
+	w.end("assessmentSection")
+	w.end("testPart")
+
+	w.start("outcomeProcessing")
+	w.start("setOutcomeValue", "identifier", qtiScore)
+	w.start("sum")
+	w.start("testVariables", "variableIdentifier", qtiScore)
+	w.end("testVariables")
+	w.end("sum")
+	w.end("setOutcomeValue")
+	w.end("outcomeProcessing")
+
+	w.end("assessmentTest")
+
+	return w.bytes()
+}
+
+// qtiItemRefs will write a reference to item of every question of quiz which belongs to pool.
+func qtiItemRefs(w *xmlWriter, quiz *models.Quiz, pool string) {
+	for i := range quiz.Questions {
+		if quiz.Questions[i].Pool != pool {
+			continue
+		}
+
+		identifier := qtiItemIdentifier(&quiz.Questions[i])
+		w.start("assessmentItemRef", "identifier", identifier, "href", qtiItemDir+identifier+".xml")
+		w.end("assessmentItemRef")
+	}
+}
+
+// qtiManifest will write the manifest of a package, listing the test and every item along with files they use.
+func qtiManifest(quiz *models.Quiz, items []qtiResource) ([]byte, error) {
+	w := newXMLWriter()
+
+	w.start("manifest", "xmlns", cpNamespace, "identifier", "M-"+quiz.ID.String())
+	w.start("metadata")
+	w.element("schema", "QTIv2.1 Package")
+	w.element("schemaversion", "1.0.0")
+	w.end("metadata")
+	w.start("organizations")
+	w.end("organizations")
+	w.start("resources")
+
+	w.start("resource", "identifier", "T-"+quiz.ID.String(), "type", "imsqti_test_xmlv2p1", "href", qtiTestFile)
+	w.start("file", "href", qtiTestFile)
+	w.end("file")
+	for _, item := range items {
+		w.start("dependency", "identifierref", item.identifier)
+		w.end("dependency")
+	}
+	w.end("resource")
+
+	for _, item := range items {
+		w.start("resource", "identifier", item.identifier, "type", item.kind, "href", item.href)
+		for _, file := range item.files {
+			w.start("file", "href", file)
+			w.end("file")
+		}
+		w.end("resource")
+	}
+
+	w.end("resources")
+	w.end("manifest")
+
+	return w.bytes()
+}
+
+// qtiItemIdentifier will return identifier of the item of question, which is also the name of its file.
+func qtiItemIdentifier(question *models.Question) string {
+	return "Q-" + question.ID.String()
+}
+
+// qtiChoiceIdentifier will return identifier of the option at index.
+func qtiChoiceIdentifier(index int) string {
+	return "O-" + strconv.Itoa(index+1)
+}
+
+// formatQTINumber will format number as a float value of QTI.
+func formatQTINumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// renderedHTML will return HTML rendered from Markdown source, rendering it if it was not stored.
+func renderedHTML(rendered, source string) string {
+	if rendered != "" {
+		return rendered
+	}
+
+	return utils.RenderMarkdown(source)
+}
+
+// writeXHTML will write sanitized HTML as XHTML which QTI allows. Elements which QTI does not allow are
+// replaced by their content, and attributes other than links, sources and classes are removed.
+func writeXHTML(w *xmlWriter, source string) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+
+	nodes, err := html.ParseFragment(strings.NewReader(source), context)
+	if err != nil {
+		w.text(htmlToText(source))
+		return
+	}
+
+	for _, node := range nodes {
+		writeXHTMLNode(w, node)
+	}
+}
+
+// writeXHTMLNode will write node along with its children as XHTML.
+func writeXHTMLNode(w *xmlWriter, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		w.text(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if !qtiElements[node.Data] {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			writeXHTMLNode(w, child)
+		}
+
+		return
+	}
+
+	var attrs []string
+	hasAlt := false
+
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && qtiAttributes[attr.Key] {
+			attrs = append(attrs, attr.Key, attr.Val)
+			hasAlt = hasAlt || attr.Key == "alt"
+		}
+	}
+
+	// alternative text is required for images.
+	if node.Data == "img" && !hasAlt {
+		attrs = append(attrs, "alt", "")
+	}
+
+	w.start(node.Data, attrs...)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeXHTMLNode(w, child)
+	}
+	w.end(node.Data)
+}
+
+// xmlWriter will write XML elements one by one, keeping the first error which occurs.
+type xmlWriter struct {
+	buffer  bytes.Buffer
+	encoder *xml.Encoder
+	err     error
+}
+
+func newXMLWriter() *xmlWriter {
+	w := &xmlWriter{}
+	w.buffer.WriteString(xml.Header)
+	w.encoder = xml.NewEncoder(&w.buffer)
+
+	return w
+}
+
+// start will write start of element with attributes given as pairs of name and value.
+func (w *xmlWriter) start(name string, attrs ...string) {
+	element := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		element.Attr = append(element.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+	}
+
+	w.encode(element)
+}
+
+// end will write end of element.
+func (w *xmlWriter) end(name string) {
+	w.encode(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// text will write text, escaping it.
+func (w *xmlWriter) text(text string) {
+	w.encode(xml.CharData(text))
+}
+
+// element will write an element containing only text.
+func (w *xmlWriter) element(name, text string, attrs ...string) {
+	w.start(name, attrs...)
+	w.text(text)
+	w.end(name)
+}
+
+func (w *xmlWriter) encode(token xml.Token) {
+	if w.err == nil {
+		w.err = w.encoder.EncodeToken(token)
+	}
+}
+
+// bytes will return the written document, or the first error which occurred while writing it.
+func (w *xmlWriter) bytes() ([]byte, error) {
+	if w.err == nil {
+		w.err = w.encoder.Flush()
+	}
+
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return w.buffer.Bytes(), nil
+}
@@ -8,36 +8,29 @@ import (
 )
 
 // MandatoryAuthMiddleware will check that authorization cookie is valid.
+// Requests which are not authorized are reported by the error handler of the app with 401 status.
 func MandatoryAuthMiddleware(c *fiber.Ctx) error {
 	authorizationTypeBearer := "bearer"
 
 	authHeader := c.Get("authorization")
 
 	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	fields := strings.Fields(authHeader)
 	if len(fields) < 2 {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "invalid authorization header provided",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid authorization header provided")
 	}
 
 	authorizationType := strings.ToLower(fields[0])
 	if authorizationType != authorizationTypeBearer {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": fmt.Sprintf("unsupported authorization type %s", authorizationType),
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("unsupported authorization type %s", authorizationType))
 	}
 
 	user, err := ValidateJWT(fields[1])
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	c.Locals("user", user)
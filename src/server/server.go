@@ -3,18 +3,26 @@ package server
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/rs/zerolog"
 	"github.com/shaileshhb/quiz/src/controller"
 	"github.com/shaileshhb/quiz/src/db"
 	"github.com/shaileshhb/quiz/src/service"
+	"github.com/shaileshhb/quiz/src/storage"
 )
 
 // Server Struct For Start the equisplit service.
 type Server struct {
 	App      *fiber.App
 	Router   fiber.Router
-	Database *db.Database
+	Database db.Repository
 	Log      zerolog.Logger
+
+	// MediaStorage keeps content of uploaded media.
+	MediaStorage storage.FileStorage
+
+	// ExpiryScheduler ends expired attempts, it is created by RegisterModuleRoutes.
+	ExpiryScheduler *service.ExpiryScheduler
 }
 
 // RegisterRoutes will be implemented by routes package methods to register their routes
@@ -22,21 +30,27 @@ type RegisterRoutes interface {
 	RegisterRoute(router fiber.Router)
 }
 
-// NewServer will initialize the server with logger and fiber router.
-func NewServer(log zerolog.Logger, database *db.Database) *Server {
+// NewServer will initialize the server with logger, database and storage of uploaded media.
+func NewServer(log zerolog.Logger, database db.Repository, mediaStorage storage.FileStorage) *Server {
 	return &Server{
-		Database: database,
-		Log:      log,
+		Database:     database,
+		Log:          log,
+		MediaStorage: mediaStorage,
 	}
 }
 
 func (ser *Server) InitializeRouter() {
 	app := fiber.New(fiber.Config{
-		AppName: "Quiz App",
+		AppName:      "Quiz App",
+		ErrorHandler: controller.NewErrorHandler(ser.Log),
 	})
 
+	// every request gets an ID, which is sent in X-Request-ID header and in errors.
+	app.Use(requestid.New())
+
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
+		AllowOrigins:  "*",
+		ExposeHeaders: fiber.HeaderXRequestID,
 	}))
 
 	app.Get("/", func(c *fiber.Ctx) error {
@@ -63,10 +77,20 @@ func (ser *Server) RegisterModuleRoutes() {
 	userserv := service.NewUserService(ser.Database)
 	usercon := controller.NewUserController(userserv, ser.Log)
 
+	questionbankserv := service.NewQuestionBankService(ser.Database)
+	questionbankcon := controller.NewQuestionBankController(questionbankserv, ser.Log)
+
+	mediaserv := service.NewMediaService(ser.Database, ser.MediaStorage)
+	mediacon := controller.NewMediaController(mediaserv, ser.Log)
+
+	quizexportserv := service.NewQuizExportService(ser.Database, ser.MediaStorage)
+	quizexportcon := controller.NewQuizExportController(quizexportserv, ser.Log)
+
 	userquizserv := service.NewUserQuizService(ser.Database)
 	userquizcon := controller.NewUserQuizController(userquizserv, ser.Log)
+	ser.ExpiryScheduler = service.NewExpiryScheduler(userquizserv, service.DefaultExpiryInterval, ser.Log)
 
 	ser.register([]RegisterRoutes{
-		quizcon, usercon, userquizcon,
+		quizcon, usercon, userquizcon, questionbankcon, mediacon, quizexportcon,
 	})
 }
@@ -0,0 +1,16 @@
+package service
+
+import "time"
+
+// Clock will return the current time. Services use it instead of time.Now so that tests can control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock will return the current time of the system.
+type systemClock struct{}
+
+// Now will return the current local time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeClock is a Clock whose time only changes when it is advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// newFakeClock will create fakeClock starting at current time.
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now()}
+}
+
+// Now will return the time of clock.
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.now
+}
+
+// Advance will move the clock forward by given duration.
+func (clock *fakeClock) Advance(duration time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.now = clock.now.Add(duration)
+}
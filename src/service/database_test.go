@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDatabases contains every supported database backend, each seeded with dummy data.
+var testDatabases = []struct {
+	name string
+	open func() (db.Repository, error)
+}{
+	{
+		name: "memory",
+		open: func() (db.Repository, error) {
+			return db.NewDatabase(), nil
+		},
+	},
+	{
+		name: "sqlite",
+		open: func() (db.Repository, error) {
+			return db.NewSQLiteDatabase(":memory:")
+		},
+	},
+}
+
+// forEachDatabase will run test against every supported database backend.
+func forEachDatabase(t *testing.T, test func(t *testing.T, database db.Repository)) {
+	for _, testDatabase := range testDatabases {
+		open := testDatabase.open
+
+		t.Run(testDatabase.name, func(t *testing.T) {
+			database, err := open()
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer database.Close()
+
+			test(t, database)
+		})
+	}
+}
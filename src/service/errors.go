@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// Kinds of errors returned by services, every Error wraps one of them. Controllers report errors by their kind,
+// errors which do not wrap any kind are unexpected.
+var (
+	ErrInvalid      = errors.New("invalid request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrGone         = errors.New("gone")
+)
+
+// Error is an error of a service along with a machine readable code which clients can rely on.
+// Errors having the same code are equal for errors.Is, so errors whose message contains details
+// still match the sentinel error they were created from.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+// newError will create an error of given kind.
+func newError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Error will return message of the error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap will return kind of the error.
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Is will check if target is an Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// withMessage will return copy of the error with a different message.
+func (e *Error) withMessage(message string) *Error {
+	return newError(e.Kind, e.Code, message)
+}
+
+// InvalidRequest will report err as an invalid request, unless it is already an Error or a validation error.
+// It is used for errors of models and formats, which are caused by the request.
+func InvalidRequest(err error) error {
+	var serviceErr *Error
+	var validationErr *models.ValidationError
+
+	if err == nil || errors.As(err, &serviceErr) || errors.As(err, &validationErr) {
+		return err
+	}
+
+	return newError(ErrInvalid, "invalid_request", err.Error())
+}
+
+// Errors of quizzes.
+var (
+	ErrQuizNotFound        = newError(ErrNotFound, "quiz_not_found", "quiz not found")
+	ErrQuizVersionNotFound = newError(ErrNotFound, "quiz_version_not_found", "quiz version not found")
+	ErrQuizTitleExists     = newError(ErrConflict, "quiz_title_exists", "quiz with same title already exists")
+	ErrQuizModified        = newError(ErrConflict, "quiz_modified", "quiz was modified concurrently, please try again")
+	ErrQuizHasAttempts     = newError(ErrConflict, "quiz_has_attempts", "quiz has been attempted by users, archive it instead")
+	ErrNotQuizCreator      = newError(ErrForbidden, "not_quiz_creator", "only creator of the quiz can modify it")
+	ErrInvalidCursor       = newError(ErrInvalid, "invalid_cursor", "invalid cursor")
+)
+
+// Errors of question bank and media.
+var (
+	ErrBankQuestionNotFound = newError(ErrNotFound, "bank_question_not_found", "bank question not found")
+	ErrBankQuestionReused   = newError(ErrInvalid, "bank_question_reused", "bank question can be added to a quiz only once")
+	ErrMediaNotFound        = newError(ErrNotFound, "media_not_found", "media not found")
+)
+
+// Errors of users.
+var (
+	ErrUserNotFound       = newError(ErrNotFound, "user_not_found", "user not found")
+	ErrUsernameExists     = newError(ErrConflict, "username_exists", "same username already exists")
+	ErrInvalidCredentials = newError(ErrUnauthorized, "invalid_credentials", "username or password is incorrect")
+)
+
+// Errors of attempts.
+var (
+	ErrAttemptNotFound     = newError(ErrNotFound, "attempt_not_found", "attempt not found")
+	ErrNoAttemptInProgress = newError(ErrNotFound, "no_attempt_in_progress", "no attempt in progress for this quiz")
+	ErrQuizNotAttempted    = newError(ErrNotFound, "quiz_not_attempted", "user not attempted specified quiz")
+	ErrQuestionNotFound    = newError(ErrNotFound, "question_not_found", "question not found")
+	ErrAttemptsExhausted   = newError(ErrConflict, "attempts_exhausted", "user has already attempted this quiz")
+	ErrAttemptInProgress   = newError(ErrConflict, "attempt_in_progress", "previous attempt of this quiz has not ended yet")
+	ErrAttemptCooldown     = newError(ErrConflict, "attempt_cooldown", "next attempt of this quiz can not be started yet")
+	ErrAttemptNotStarted   = newError(ErrConflict, "attempt_not_started", "please start quiz before submitting answers")
+	ErrQuestionAnswered    = newError(ErrConflict, "question_answered", "question already answered")
+	ErrAttemptBusy         = newError(ErrConflict, "attempt_busy", "attempt is being updated concurrently, please try again")
+	ErrTimeExceeded        = newError(ErrGone, "time_exceeded", "maximum time exceeded for this quiz")
+	ErrAttemptEnded        = newError(ErrGone, "attempt_ended", "quiz has already ended")
+	ErrQuizArchived        = newError(ErrGone, "quiz_archived", "quiz is archived and cannot be attempted")
+	ErrQuizClosed          = newError(ErrGone, "quiz_closed", "quiz has closed and cannot be attempted")
+)
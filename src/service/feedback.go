@@ -0,0 +1,94 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// isFeedbackRevealed will check if feedback mode of quiz allows user to see whether answers of the attempt
+// are correct at given time.
+func isFeedbackRevealed(quiz *models.Quiz, attempt *models.UserQuizAttempts, now time.Time) bool {
+	switch feedbackMode(quiz) {
+	case models.FeedbackAfterAttempt:
+		return attempt.EndedAt != nil
+	case models.FeedbackAfterClose:
+		return isQuizClosed(quiz, now)
+	case models.FeedbackNever:
+		return false
+	default:
+		return true
+	}
+}
+
+// feedbackMode will return feedback mode of quiz, quizzes created before feedback modes were introduced
+// reveal feedback immediately.
+func feedbackMode(quiz *models.Quiz) string {
+	if quiz.FeedbackMode == "" {
+		return models.FeedbackImmediate
+	}
+
+	return quiz.FeedbackMode
+}
+
+// isQuizClosed will check if quiz can no longer be attempted at given time.
+func isQuizClosed(quiz *models.Quiz, now time.Time) bool {
+	return quiz.ClosesAt != nil && !now.Before(*quiz.ClosesAt)
+}
+
+// hideFeedback will remove correctness and score of every response of attempt unless feedback mode of quiz
+// reveals it at given time. Score of an attempt in progress is removed as well, because its change after
+// every answer would reveal whether the answer was correct.
+func hideFeedback(attempt *models.UserQuizAttempts, quiz *models.Quiz, now time.Time) {
+	if isFeedbackRevealed(quiz, attempt, now) {
+		return
+	}
+
+	if attempt.UserResponses != nil {
+		responses := make([]models.UserResponse, len(attempt.UserResponses))
+		for i, response := range attempt.UserResponses {
+			response.IsCorrect = nil
+			response.Score = nil
+			responses[i] = response
+		}
+		attempt.UserResponses = responses
+	}
+
+	if attempt.EndedAt == nil {
+		attempt.TotalScore = 0
+		attempt.Percentage = 0
+	}
+}
+
+// addExplanations will copy explanations of questions and feedback of options of quiz into its copy shown to users.
+// Questions and options of the copy are expected to be in the same order as quiz.
+func addExplanations(quizCopy, quiz *models.Quiz) {
+	for i := range quizCopy.Questions {
+		quizCopy.Questions[i].Explanation = quiz.Questions[i].Explanation
+		quizCopy.Questions[i].ExplanationHTML = quiz.Questions[i].ExplanationHTML
+
+		for j := range quizCopy.Questions[i].Options {
+			quizCopy.Questions[i].Options[j].Feedback = quiz.Questions[i].Options[j].Feedback
+			quizCopy.Questions[i].Options[j].FeedbackHTML = quiz.Questions[i].Options[j].FeedbackHTML
+		}
+	}
+}
+
+// selectedOptions will return options of question selected in the response, along with their correctness and feedback.
+func selectedOptions(question *models.Question, response *models.UserResponse) []models.Option {
+	selected := map[uuid.UUID]bool{response.SelectedOptionID: true}
+	for _, optionID := range response.SelectedOptionIDs {
+		selected[optionID] = true
+	}
+
+	var options []models.Option
+
+	for _, option := range question.Options {
+		if selected[option.ID] {
+			options = append(options, option)
+		}
+	}
+
+	return options
+}
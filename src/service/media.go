@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"  // register decoder used to check dimensions of gif images
+	_ "image/jpeg" // register decoder used to check dimensions of jpeg images
+	_ "image/png"  // register decoder used to check dimensions of png images
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/storage"
+)
+
+// MediaService will consist of service methods that would be implemented by mediaService
+type MediaService interface {
+	Upload(fileName string, content io.Reader, userID uuid.UUID) (*models.Media, error)
+	GetMedia(mediaID, userID uuid.UUID) (*models.Media, error)
+	Open(mediaID uuid.UUID) (io.ReadCloser, error)
+}
+
+// mediaService will contain reference to db and storage keeping content of uploaded files.
+type mediaService struct {
+	db      db.Repository
+	storage storage.FileStorage
+}
+
+// NewMediaService will create new instance of mediaService
+func NewMediaService(db db.Repository, storage storage.FileStorage) MediaService {
+	return &mediaService{
+		db:      db,
+		storage: storage,
+	}
+}
+
+// Upload will store uploaded image after validating its size, content type and dimensions.
+// Content type is detected from the content, type sent by the client is not trusted.
+func (service *mediaService) Upload(fileName string, content io.Reader, userID uuid.UUID) (*models.Media, error) {
+	// one more byte is read to know if the file is too large.
+	data, err := io.ReadAll(io.LimitReader(content, models.MaxMediaSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	media := &models.Media{
+		ID:          uuid.New(),
+		CreatedBy:   userID,
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+		FileName:    filepath.Base(filepath.Clean("/" + fileName)),
+		ContentType: http.DetectContentType(data),
+		Size:        int64(len(data)),
+	}
+
+	// only the header is decoded, images which are too large are rejected before decoding pixels.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err == nil {
+		media.Width = config.Width
+		media.Height = config.Height
+	}
+
+	err = media.Validate()
+	if err != nil {
+		return nil, InvalidRequest(err)
+	}
+
+	err = service.storage.Save(media.ID.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.db.CreateMedia(media)
+	if err != nil {
+		// file is not referred by any record, so it is removed.
+		service.storage.Delete(media.ID.String())
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// GetMedia will fetch details of uploaded media which user can download. User can download media they have uploaded,
+// media attached to the current version of a quiz, which every user can view, and media attached to a version of quiz
+// they have attempted. Media which user can not download is reported as not found, same as media which does not exist.
+func (service *mediaService) GetMedia(mediaID, userID uuid.UUID) (*models.Media, error) {
+	media, err := service.db.GetMediaByID(mediaID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrMediaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if media.CreatedBy == userID {
+		return media, nil
+	}
+
+	canDownload, err := service.canDownload(mediaID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canDownload {
+		return nil, ErrMediaNotFound
+	}
+
+	return media, nil
+}
+
+// canDownload will check if user can view any version of quiz which attaches media.
+func (service *mediaService) canDownload(mediaID, userID uuid.UUID) (bool, error) {
+	versions, err := service.db.ListQuizVersionsByMedia(mediaID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, version := range versions {
+		quiz, err := service.db.GetQuizByID(version.ID)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		if quiz.Version == version.Version {
+			return true, nil
+		}
+
+		// questions of previous versions are shown only to users who attempted them.
+		attempts, err := service.db.ListAttemptsByUserAndQuiz(userID, version.ID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, attempt := range attempts {
+			if attempt.QuizVersion == version.Version {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Open will open content of uploaded media for reading, caller must close it.
+func (service *mediaService) Open(mediaID uuid.UUID) (io.ReadCloser, error) {
+	content, err := service.storage.Open(mediaID.String())
+	if errors.Is(err, storage.ErrFileNotFound) {
+		return nil, ErrMediaNotFound
+	}
+
+	return content, err
+}
+
+// checkAttachedMedia will check that every media attached to questions and their options was uploaded by given user.
+func checkAttachedMedia(repo db.Repository, questions []models.Question, userID uuid.UUID) error {
+	for _, question := range questions {
+		mediaIDs := append([]uuid.UUID(nil), question.MediaIDs...)
+		for _, option := range question.Options {
+			mediaIDs = append(mediaIDs, option.MediaIDs...)
+		}
+
+		for _, mediaID := range mediaIDs {
+			media, err := repo.GetMediaByID(mediaID)
+			if errors.Is(err, db.ErrRecordNotFound) || (err == nil && media.CreatedBy != userID) {
+				return ErrMediaNotFound
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
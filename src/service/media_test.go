@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestImage will encode a png image of given dimensions.
+func createTestImage(t *testing.T, width, height int) []byte {
+	var buffer bytes.Buffer
+
+	err := png.Encode(&buffer, image.NewGray(image.Rect(0, 0, width, height)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// newTestMediaService will create media service storing files in a temporary directory.
+func newTestMediaService(t *testing.T, database db.Repository) MediaService {
+	mediaStorage, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewMediaService(database, mediaStorage)
+}
+
+// TestUploadMedia will test that uploaded images are stored along with their detected type and dimensions.
+func TestUploadMedia(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		mediaService := newTestMediaService(t, database)
+		userID := uuid.New()
+		content := createTestImage(t, 30, 20)
+
+		media, err := mediaService.Upload("../diagram.png", bytes.NewReader(content), userID)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, "diagram.png", media.FileName)
+		assert.Equal(t, "image/png", media.ContentType)
+		assert.Equal(t, int64(len(content)), media.Size)
+		assert.Equal(t, 30, media.Width)
+		assert.Equal(t, 20, media.Height)
+		assert.Equal(t, userID, media.CreatedBy)
+
+		storedMedia, err := mediaService.GetMedia(media.ID, userID)
+		assert.Nil(t, err)
+		assert.Equal(t, media.ContentType, storedMedia.ContentType)
+
+		file, err := mediaService.Open(media.ID)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer file.Close()
+
+		storedContent, err := io.ReadAll(file)
+		assert.Nil(t, err)
+		assert.Equal(t, content, storedContent)
+
+		_, err = mediaService.GetMedia(uuid.New(), userID)
+		assert.Equal(t, "media not found", err.Error())
+	})
+}
+
+// TestUploadInvalidMedia will test that files which are not supported images or are too large are rejected.
+func TestUploadInvalidMedia(t *testing.T) {
+	tests := map[string]struct {
+		content []byte
+		err     string
+	}{
+		"empty file":        {content: nil, err: "file must be a png, jpeg or gif image"},
+		"text file":         {content: []byte("<svg onload=alert(1)>"), err: "file must be a png, jpeg or gif image"},
+		"corrupted image":   {content: []byte("\x89PNG\r\n\x1a\n corrupted"), err: "image width and height should be between 1 and 4096 pixels"},
+		"too large image":   {content: createTestImage(t, models.MaxMediaDimension+1, 1), err: "image width and height should be between 1 and 4096 pixels"},
+		"too large content": {content: append(createTestImage(t, 1, 1), make([]byte, models.MaxMediaSize)...), err: "file should not exceed 2 MB"},
+	}
+
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		mediaService := newTestMediaService(t, database)
+
+		for name, test := range tests {
+			_, err := mediaService.Upload("image.png", bytes.NewReader(test.content), uuid.New())
+			if assert.NotNil(t, err, name) {
+				assert.Equal(t, test.err, err.Error(), name)
+			}
+		}
+	})
+}
+
+// TestAttachMedia will test that questions and options can only refer to media uploaded by creator of the quiz.
+func TestAttachMedia(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		mediaService := newTestMediaService(t, database)
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 1)
+
+		media, err := mediaService.Upload("diagram.png", bytes.NewReader(createTestImage(t, 10, 10)), quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		otherMedia, err := mediaService.Upload("diagram.png", bytes.NewReader(createTestImage(t, 10, 10)), uuid.New())
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		quiz.Questions[0].Options[0].MediaIDs = []uuid.UUID{otherMedia.ID}
+		err = quizService.Update(quiz, quiz.CreatedBy)
+		assert.Equal(t, "media not found", err.Error())
+
+		quiz.Questions[0].MediaIDs = []uuid.UUID{media.ID}
+		quiz.Questions[0].Options[0].MediaIDs = []uuid.UUID{media.ID}
+		err = quizService.Update(quiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(2), quiz.Version)
+
+		storedQuiz, err := quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []uuid.UUID{media.ID}, storedQuiz.Questions[0].MediaIDs)
+		assert.Equal(t, []uuid.UUID{media.ID}, storedQuiz.Questions[0].Options[0].MediaIDs)
+
+		diff, err := quizService.DiffQuizVersions(quiz.ID, 1, 2, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Len(t, diff.Changes, 2)
+	})
+}
+
+// TestDownloadMedia will test that users can download only media they uploaded or can see in a quiz.
+func TestDownloadMedia(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		mediaService := newTestMediaService(t, database)
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 1)
+		attemptedBy := createTestUser(t, database)
+		otherUserID := createTestUser(t, database)
+
+		media, err := mediaService.Upload("diagram.png", bytes.NewReader(createTestImage(t, 10, 10)), quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		// media which is not attached yet can be downloaded only by the user who uploaded it.
+		_, err = mediaService.GetMedia(media.ID, otherUserID)
+		assert.Equal(t, ErrMediaNotFound, err)
+
+		_, err = mediaService.GetMedia(media.ID, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		quiz.Questions[0].Options[0].MediaIDs = []uuid.UUID{media.ID}
+		err = quizService.Update(quiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		_, err = mediaService.GetMedia(media.ID, otherUserID)
+		assert.Nil(t, err)
+
+		err = NewUserQuizService(database).StartQuiz(&models.UserQuizAttempts{UserID: attemptedBy, QuizID: quiz.ID})
+		assert.Nil(t, err)
+
+		// once media is removed from the quiz, only users who attempted a version attaching it can download it.
+		quiz.Questions[0].Options[0].MediaIDs = nil
+		err = quizService.Update(quiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		_, err = mediaService.GetMedia(media.ID, otherUserID)
+		assert.Equal(t, ErrMediaNotFound, err)
+
+		_, err = mediaService.GetMedia(media.ID, attemptedBy)
+		assert.Nil(t, err)
+
+		_, err = mediaService.GetMedia(media.ID, quiz.CreatedBy)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// finishAttempt will end attempt and judge whether it has passed against passing percentage of quiz.
+func finishAttempt(attempt *models.UserQuizAttempts, quiz *models.Quiz, endedAt time.Time) {
+	attempt.EndedAt = &endedAt
+	attempt.PassingPercentage = quiz.PassingPercentage
+	attempt.Outcome = models.OutcomeFailed
+
+	if attempt.Percentage >= quiz.PassingPercentage {
+		attempt.Outcome = models.OutcomePassed
+	}
+}
+
+// skipUnansweredQuestions will add a skipped response without any score for every question of quiz
+// which has not been answered in the attempt.
+func skipUnansweredQuestions(attempt *models.UserQuizAttempts, quiz *models.Quiz) {
+	answered := map[uuid.UUID]bool{}
+	for _, response := range attempt.UserResponses {
+		answered[response.QuestionID] = true
+	}
+
+	for _, question := range quiz.Questions {
+		if answered[question.ID] {
+			continue
+		}
+
+		attempt.UserResponses = append(attempt.UserResponses, models.UserResponse{
+			ID:                uuid.New(),
+			UserID:            attempt.UserID,
+			QuizID:            attempt.QuizID,
+			UserQuizAttemptID: attempt.ID,
+			QuestionID:        question.ID,
+			BankQuestionID:    question.BankQuestionID,
+			IsCorrect:         new(bool),
+			Score:             new(float64),
+			IsSkipped:         true,
+		})
+	}
+}
+
+// expireAttempt will end attempt whose maximum time has exceeded at its deadline.
+// Outcome is timed out, or abandoned if no question was answered.
+func expireAttempt(attempt *models.UserQuizAttempts, quiz *models.Quiz) {
+	endedAt := attempt.StartedAt.Add(maxDuration(quiz))
+	attempt.EndedAt = &endedAt
+	attempt.PassingPercentage = quiz.PassingPercentage
+	attempt.Outcome = models.OutcomeTimedOut
+
+	if len(attempt.UserResponses) == 0 {
+		attempt.Outcome = models.OutcomeAbandoned
+	}
+}
+
+// isAttemptExpired will check if attempt has not ended even though maximum time of quiz has exceeded at given time.
+func isAttemptExpired(attempt *models.UserQuizAttempts, quiz *models.Quiz, now time.Time) bool {
+	return attempt.EndedAt == nil && now.Sub(*attempt.StartedAt) > maxDuration(quiz)
+}
+
+// maxDuration will return maximum time allowed for an attempt of quiz.
+func maxDuration(quiz *models.Quiz) time.Duration {
+	return time.Duration(quiz.MaxTime * uint64(time.Minute))
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// drawQuestions will return IDs of questions of quiz asked in a new attempt, in the order they appear in quiz.
+// Questions which are not part of a pool are always asked, while draw count of questions is drawn randomly from every pool.
+func drawQuestions(quiz *models.Quiz) []uuid.UUID {
+	poolQuestions := map[string][]uuid.UUID{}
+	for _, question := range quiz.Questions {
+		if question.Pool != "" {
+			poolQuestions[question.Pool] = append(poolQuestions[question.Pool], question.ID)
+		}
+	}
+
+	drawn := map[uuid.UUID]bool{}
+
+	for _, pool := range quiz.Pools {
+		questionIDs := poolQuestions[pool.Name]
+
+		for i, j := range rand.Perm(len(questionIDs)) {
+			if i == pool.DrawCount {
+				break
+			}
+			drawn[questionIDs[j]] = true
+		}
+	}
+
+	questionIDs := []uuid.UUID{}
+	for _, question := range quiz.Questions {
+		if question.Pool == "" || drawn[question.ID] {
+			questionIDs = append(questionIDs, question.ID)
+		}
+	}
+
+	return questionIDs
+}
+
+// restrictToAttempt will remove questions of quiz which were not drawn for attempt.
+// Attempts started before pools were introduced did not record their questions and have all questions of quiz.
+func restrictToAttempt(quiz *models.Quiz, attempt *models.UserQuizAttempts) {
+	if attempt.QuestionIDs == nil {
+		return
+	}
+
+	drawn := map[uuid.UUID]bool{}
+	for _, questionID := range attempt.QuestionIDs {
+		drawn[questionID] = true
+	}
+
+	questions := []models.Question{}
+	for _, question := range quiz.Questions {
+		if drawn[question.ID] {
+			questions = append(questions, question)
+		}
+	}
+
+	quiz.Questions = questions
+}
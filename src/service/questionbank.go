@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// QuestionBankService will consist of service methods that would be implemented by questionBankService
+type QuestionBankService interface {
+	Create(question *models.BankQuestion) error
+	GetQuestion(questionID, userID uuid.UUID) (*models.BankQuestion, error)
+	List(userID uuid.UUID, tag string) ([]models.BankQuestion, error)
+	Update(question *models.BankQuestion, userID uuid.UUID) error
+	Delete(questionID, userID uuid.UUID) error
+	GetStatistics(questionID, userID uuid.UUID) (*models.BankQuestionStatistics, error)
+}
+
+// questionBankService will contain reference to db.
+type questionBankService struct {
+	db db.Repository
+}
+
+// NewQuestionBankService will create new instance of questionBankService
+func NewQuestionBankService(db db.Repository) QuestionBankService {
+	return &questionBankService{db: db}
+}
+
+// Create will add new question to question bank of its creator.
+func (service *questionBankService) Create(question *models.BankQuestion) error {
+	question.ID = uuid.New()
+	question.QuizID = uuid.Nil
+	question.Pool = ""
+	// both storage backends sort questions by creation time in milliseconds.
+	question.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	assignBankOptionIDs(question, nil)
+	question.RenderHTML()
+
+	err := checkAttachedMedia(service.db, []models.Question{question.Question}, question.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	return service.db.CreateBankQuestion(question)
+}
+
+// GetQuestion will fetch question of question bank. Only creator of the question can view it.
+func (service *questionBankService) GetQuestion(questionID, userID uuid.UUID) (*models.BankQuestion, error) {
+	return service.getOwnedQuestion(questionID, userID)
+}
+
+// List will fetch questions of question bank created by the user, optionally having given tag.
+func (service *questionBankService) List(userID uuid.UUID, tag string) ([]models.BankQuestion, error) {
+	return service.db.ListBankQuestions(userID, tag)
+}
+
+// Update will replace question of question bank. Only creator of the question can update it.
+// Quizzes which already use the question keep their copy until they are updated.
+func (service *questionBankService) Update(question *models.BankQuestion, userID uuid.UUID) error {
+	currentQuestion, err := service.getOwnedQuestion(question.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	question.QuizID = uuid.Nil
+	question.Pool = ""
+	question.CreatedBy = currentQuestion.CreatedBy
+	question.CreatedAt = currentQuestion.CreatedAt
+	assignBankOptionIDs(question, currentQuestion)
+	question.RenderHTML()
+
+	err = checkAttachedMedia(service.db, []models.Question{question.Question}, question.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	err = service.db.UpdateBankQuestion(question)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrBankQuestionNotFound
+	}
+
+	return err
+}
+
+// Delete will remove question from question bank. Only creator of the question can delete it.
+// Quizzes using the question are not affected as they contain a copy of it.
+func (service *questionBankService) Delete(questionID, userID uuid.UUID) error {
+	_, err := service.getOwnedQuestion(questionID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = service.db.DeleteBankQuestion(questionID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrBankQuestionNotFound
+	}
+
+	return err
+}
+
+// GetStatistics will aggregate responses submitted for the question across all quizzes using it.
+func (service *questionBankService) GetStatistics(questionID, userID uuid.UUID) (*models.BankQuestionStatistics, error) {
+	_, err := service.getOwnedQuestion(questionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := service.db.ListResponsesByBankQuestion(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := &models.BankQuestionStatistics{
+		BankQuestionID: questionID,
+		Quizzes:        []models.QuizQuestionStatistics{},
+	}
+
+	quizResponses := map[uuid.UUID][]models.UserResponse{}
+	for _, response := range responses {
+		quizResponses[response.QuizID] = append(quizResponses[response.QuizID], response)
+	}
+
+	statistics.QuestionStatistics = questionStatistics(responses)
+
+	for quizID, responses := range quizResponses {
+		statistics.Quizzes = append(statistics.Quizzes, models.QuizQuestionStatistics{
+			QuizID:             quizID,
+			QuestionStatistics: questionStatistics(responses),
+		})
+	}
+
+	sort.Slice(statistics.Quizzes, func(i, j int) bool {
+		return statistics.Quizzes[i].QuizID.String() < statistics.Quizzes[j].QuizID.String()
+	})
+
+	return statistics, nil
+}
+
+// getOwnedQuestion will fetch question of question bank and check that it was created by given user.
+// Questions of other users are reported as not found.
+func (service *questionBankService) getOwnedQuestion(questionID, userID uuid.UUID) (*models.BankQuestion, error) {
+	question, err := service.db.GetBankQuestionByID(questionID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrBankQuestionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if question.CreatedBy != userID {
+		return nil, ErrBankQuestionNotFound
+	}
+
+	return question, nil
+}
+
+// assignBankOptionIDs will assign IDs to options of bank question. IDs of options which exist
+// in currentQuestion are kept, every other option gets a new ID.
+func assignBankOptionIDs(question, currentQuestion *models.BankQuestion) {
+	currentOptions := map[uuid.UUID]bool{}
+
+	if currentQuestion != nil {
+		for _, option := range currentQuestion.Options {
+			currentOptions[option.ID] = true
+		}
+	}
+
+	for i := range question.Options {
+		if !currentOptions[question.Options[i].ID] {
+			question.Options[i].ID = uuid.New()
+		}
+
+		question.Options[i].QuestionID = question.ID
+	}
+}
+
+// questionStatistics will compute statistics of given responses to a question.
+func questionStatistics(responses []models.UserResponse) models.QuestionStatistics {
+	statistics := models.QuestionStatistics{TotalResponses: len(responses)}
+	totalScore := 0.0
+
+	for _, response := range responses {
+		if response.IsCorrect != nil && *response.IsCorrect {
+			statistics.TotalCorrect++
+		}
+
+		if response.IsSkipped {
+			statistics.TotalSkipped++
+		}
+
+		if response.Score != nil {
+			totalScore += *response.Score
+		}
+	}
+
+	statistics.CorrectPercentage = percentage(float64(statistics.TotalCorrect), float64(statistics.TotalResponses))
+
+	if statistics.TotalResponses > 0 {
+		statistics.AverageScore = math.Round(totalScore/float64(statistics.TotalResponses)*100) / 100
+	}
+
+	return statistics
+}
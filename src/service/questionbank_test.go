@@ -0,0 +1,211 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestBankQuestion will add a single choice question to question bank whose first option is correct.
+func createTestBankQuestion(t *testing.T, database db.Repository, createdBy uuid.UUID, tags ...string) *models.BankQuestion {
+	trueValue := true
+	falseValue := false
+
+	question := models.BankQuestion{
+		Question: models.Question{
+			Text:   "Bank question",
+			Points: 2,
+			Options: []models.Option{
+				{Answer: "Answer 1", IsCorrect: &trueValue},
+				{Answer: "Answer 2", IsCorrect: &falseValue},
+			},
+		},
+		CreatedBy: createdBy,
+		Tags:      tags,
+	}
+
+	err := question.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewQuestionBankService(database).Create(&question)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &question
+}
+
+// createTestUser will register a new user directly in database.
+func createTestUser(t *testing.T, database db.Repository) uuid.UUID {
+	user := models.User{
+		ID:       uuid.New(),
+		Username: uuid.NewString(),
+	}
+
+	err := database.CreateUser(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user.ID
+}
+
+// TestQuestionBank will test that only creator of bank question can view, list, modify and use it in quizzes.
+func TestQuestionBank(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		bankService := NewQuestionBankService(database)
+		quizService := NewQuizService(database)
+		createdBy := uuid.New()
+
+		question := createTestBankQuestion(t, database, createdBy, "Science")
+		createTestBankQuestion(t, database, createdBy, "history")
+		createTestBankQuestion(t, database, uuid.New(), "science")
+
+		_, err := bankService.GetQuestion(question.ID, uuid.New())
+		assert.Equal(t, "bank question not found", err.Error())
+
+		storedQuestion, err := bankService.GetQuestion(question.ID, createdBy)
+		assert.Nil(t, err)
+		assert.Equal(t, question.Text, storedQuestion.Text)
+		assert.Equal(t, question.ID, storedQuestion.Options[0].QuestionID)
+
+		questions, err := bankService.List(createdBy, "")
+		assert.Nil(t, err)
+		assert.Len(t, questions, 2)
+
+		questions, err = bankService.List(createdBy, "SCIENCE")
+		assert.Nil(t, err)
+		assert.Len(t, questions, 1)
+		assert.Equal(t, question.ID, questions[0].ID)
+
+		bankQuestionID := question.ID
+		quiz := models.Quiz{
+			Title:     "Bank Quiz",
+			CreatedBy: uuid.New(),
+			Questions: []models.Question{{BankQuestionID: &bankQuestionID}},
+		}
+
+		err = quizService.Create(&quiz)
+		assert.Equal(t, "bank question not found", err.Error())
+
+		quiz.CreatedBy = createdBy
+		quiz.Questions = []models.Question{{BankQuestionID: &bankQuestionID}, {BankQuestionID: &bankQuestionID}}
+		err = quizService.Create(&quiz)
+		assert.Equal(t, "bank question can be added to a quiz only once", err.Error())
+
+		quiz.Questions = []models.Question{{BankQuestionID: &bankQuestionID}}
+		err = quizService.Create(&quiz)
+		assert.Nil(t, err)
+		assert.Equal(t, "Bank question", quiz.Questions[0].Text)
+		assert.Equal(t, 2.0, quiz.Questions[0].Points)
+		assert.Equal(t, quiz.Questions[0].ID, quiz.Questions[0].Options[0].QuestionID)
+
+		// updating quiz with the same bank question should not create a new version.
+		quiz.Questions = []models.Question{{ID: quiz.Questions[0].ID, BankQuestionID: &bankQuestionID}}
+		err = quizService.Update(&quiz, createdBy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), quiz.Version)
+
+		question.Text = "Updated bank question"
+		err = bankService.Update(question, uuid.New())
+		assert.Equal(t, "bank question not found", err.Error())
+
+		err = bankService.Update(question, createdBy)
+		assert.Nil(t, err)
+
+		storedQuiz, err := quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Bank question", storedQuiz.Questions[0].Text)
+
+		err = bankService.Delete(question.ID, createdBy)
+		assert.Nil(t, err)
+
+		_, err = bankService.GetQuestion(question.ID, createdBy)
+		assert.Equal(t, "bank question not found", err.Error())
+
+		storedQuiz, err = quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.Len(t, storedQuiz.Questions, 1)
+	})
+}
+
+// TestQuestionBankStatistics will test that responses to bank question are aggregated across quizzes using it.
+func TestQuestionBankStatistics(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		bankService := NewQuestionBankService(database)
+		quizService := NewQuizService(database)
+		userQuizService := NewUserQuizService(database)
+		createdBy := uuid.New()
+
+		question := createTestBankQuestion(t, database, createdBy)
+		bankQuestionID := question.ID
+
+		var quizzes []*models.Quiz
+
+		for _, title := range []string{"Bank Quiz 1", "Bank Quiz 2"} {
+			quiz := &models.Quiz{
+				Title:     title,
+				CreatedBy: createdBy,
+				Questions: []models.Question{{BankQuestionID: &bankQuestionID}},
+			}
+
+			err := quizService.Create(quiz)
+			assert.Nil(t, err)
+
+			quizzes = append(quizzes, quiz)
+		}
+
+		// first quiz is answered correctly by one user and wrongly by another, second quiz is skipped.
+		for _, optionIndex := range []int{0, 1} {
+			attempt := models.UserQuizAttempts{UserID: createTestUser(t, database), QuizID: quizzes[0].ID}
+			err := userQuizService.StartQuiz(&attempt)
+			assert.Nil(t, err)
+
+			_, err = userQuizService.SubmitAnswer(&models.UserResponse{
+				UserID:            attempt.UserID,
+				QuizID:            attempt.QuizID,
+				UserQuizAttemptID: attempt.ID,
+				QuestionID:        quizzes[0].Questions[0].ID,
+				SelectedOptionID:  quizzes[0].Questions[0].Options[optionIndex].ID,
+			})
+			assert.Nil(t, err)
+		}
+
+		attempt := models.UserQuizAttempts{UserID: createTestUser(t, database), QuizID: quizzes[1].ID}
+		err := userQuizService.StartQuiz(&attempt)
+		assert.Nil(t, err)
+
+		_, err = userQuizService.FinishAttempt(attempt.UserID, attempt.QuizID, attempt.ID)
+		assert.Nil(t, err)
+
+		_, err = bankService.GetStatistics(question.ID, uuid.New())
+		assert.Equal(t, "bank question not found", err.Error())
+
+		statistics, err := bankService.GetStatistics(question.ID, createdBy)
+		assert.Nil(t, err)
+		assert.Equal(t, question.ID, statistics.BankQuestionID)
+		assert.Equal(t, 3, statistics.TotalResponses)
+		assert.Equal(t, 1, statistics.TotalCorrect)
+		assert.Equal(t, 1, statistics.TotalSkipped)
+		assert.Equal(t, 33.33, statistics.CorrectPercentage)
+		assert.Equal(t, 0.67, statistics.AverageScore)
+		assert.Len(t, statistics.Quizzes, 2)
+
+		for _, quizStatistics := range statistics.Quizzes {
+			if quizStatistics.QuizID == quizzes[0].ID {
+				assert.Equal(t, 2, quizStatistics.TotalResponses)
+				assert.Equal(t, 50.0, quizStatistics.CorrectPercentage)
+				assert.Equal(t, 1.0, quizStatistics.AverageScore)
+			} else {
+				assert.Equal(t, quizzes[1].ID, quizStatistics.QuizID)
+				assert.Equal(t, 1, quizStatistics.TotalSkipped)
+				assert.Equal(t, 0.0, quizStatistics.CorrectPercentage)
+			}
+		}
+	})
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"strings"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/db"
@@ -13,15 +16,23 @@ import (
 type QuizService interface {
 	Create(quiz *models.Quiz) error
 	GetQuiz(quizID uuid.UUID) (*models.Quiz, error)
+	List(query *models.QuizQuery, cursor string) (*models.QuizList, error)
+	Update(quiz *models.Quiz, userID uuid.UUID) error
+	Patch(quizID uuid.UUID, patch *models.QuizPatch, userID uuid.UUID) (*models.Quiz, error)
+	Delete(quizID, userID uuid.UUID) error
+	GetQuizVersions(quizID, userID uuid.UUID) ([]models.Quiz, error)
+	GetQuizVersion(quizID uuid.UUID, version uint32, userID uuid.UUID) (*models.Quiz, error)
+	DiffQuizVersions(quizID uuid.UUID, fromVersion, toVersion uint32, userID uuid.UUID) (*models.QuizDiff, error)
+	Import(format string, content io.Reader, title string, userID uuid.UUID) (*models.ImportReport, error)
 }
 
 // quizService will contain reference to db.
 type quizService struct {
-	db *db.Database
+	db db.Repository
 }
 
 // NewQuizService will create new instance of quizService
-func NewQuizService(db *db.Database) QuizService {
+func NewQuizService(db db.Repository) QuizService {
 	return &quizService{db: db}
 }
 
@@ -38,56 +49,430 @@ func (service *quizService) Create(quiz *models.Quiz) error {
 
 	service.assignIDs(quiz)
 
-	service.db.Quiz = append(service.db.Quiz, *quiz)
-	return nil
+	err = service.resolveBankQuestions(quiz, quiz.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	err = checkAttachedMedia(service.db, quiz.Questions, quiz.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	renderQuestions(quiz)
+
+	quiz.Version = 1
+	// both storage backends sort quizzes by creation time in milliseconds.
+	quiz.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+
+	err = service.db.CreateQuiz(quiz)
+	if errors.Is(err, db.ErrDuplicateRecord) {
+		return ErrQuizTitleExists
+	}
+
+	return err
 }
 
 // GetQuiz will get quiz by ID from database.
 func (service *quizService) GetQuiz(quizID uuid.UUID) (*models.Quiz, error) {
-	currentQuiz := models.Quiz{}
-	isQuizFound := false
+	quiz, err := service.db.GetQuizByID(quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	currentQuiz := copyQuiz(*quiz)
+	return &currentQuiz, nil
+}
 
-	for _, q := range service.db.Quiz {
-		if q.ID == quizID {
-			isQuizFound = true
+// List will fetch a page of quizzes matching the query, starting after the quiz encoded in cursor.
+// Cursor of the next page is returned along with quizzes, it is empty for the last page.
+func (service *quizService) List(query *models.QuizQuery, cursor string) (*models.QuizList, error) {
+	pageQuery := *query
+	// one more quiz is fetched to know if there is a next page.
+	pageQuery.Limit++
 
-			currentQuiz = copyQuiz(q)
-			break
+	if cursor != "" {
+		position, err := decodeCursor(cursor, query)
+		if err != nil {
+			return nil, err
 		}
+		pageQuery.After = position
 	}
 
-	if !isQuizFound {
-		return nil, errors.New("quiz not found")
+	quizzes, total, err := service.db.ListQuizzes(&pageQuery)
+	if err != nil {
+		return nil, err
 	}
 
-	return &currentQuiz, nil
+	quizList := &models.QuizList{
+		Quizzes: []models.QuizSummary{},
+		Total:   total,
+	}
+
+	if len(quizzes) > query.Limit {
+		quizzes = quizzes[:query.Limit]
+		quizList.NextCursor = encodeCursor(&quizzes[query.Limit-1], query)
+	}
+
+	for _, quiz := range quizzes {
+		quizList.Quizzes = append(quizList.Quizzes, summarizeQuiz(quiz))
+	}
+
+	return quizList, nil
+}
+
+// Update will replace title, time and questions of quiz. Only creator of the quiz can update it.
+// IDs of questions and options which are present in the existing quiz are kept. If anything has changed
+// a new version of quiz is created, attempts which have already started keep using their version.
+func (service *quizService) Update(quiz *models.Quiz, userID uuid.UUID) error {
+	currentQuiz, err := service.getOwnedQuiz(quiz.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if quiz.MaxTime == 0 {
+		quiz.MaxTime = 2
+	}
+
+	quiz.CreatedBy = currentQuiz.CreatedBy
+	quiz.CreatedAt = currentQuiz.CreatedAt
+	quiz.IsArchived = currentQuiz.IsArchived
+	quiz.Version = currentQuiz.Version
+	quiz.Revision = currentQuiz.Revision
+	service.assignQuestionIDs(quiz, currentQuiz)
+
+	err = service.resolveBankQuestions(quiz, userID)
+	if err != nil {
+		return err
+	}
+
+	err = checkAttachedMedia(service.db, quiz.Questions, quiz.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	renderQuestions(quiz)
+
+	if len(diffQuizzes(currentQuiz, quiz)) > 0 {
+		quiz.Version++
+	}
+
+	return service.updateQuiz(quiz)
+}
+
+// Patch will update fields of quiz specified in the patch. Only creator of the quiz can update it.
+func (service *quizService) Patch(quizID uuid.UUID, patch *models.QuizPatch, userID uuid.UUID) (*models.Quiz, error) {
+	quiz, err := service.getOwnedQuiz(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentQuiz := *quiz
+
+	if patch.Title != nil {
+		quiz.Title = *patch.Title
+	}
+
+	if patch.MaxTime != nil {
+		quiz.MaxTime = *patch.MaxTime
+		if quiz.MaxTime == 0 {
+			quiz.MaxTime = 2
+		}
+	}
+
+	if patch.NegativeMarking != nil {
+		quiz.NegativeMarking = *patch.NegativeMarking
+	}
+
+	if patch.PassingPercentage != nil {
+		quiz.PassingPercentage = *patch.PassingPercentage
+	}
+
+	if patch.MaxAttempts != nil {
+		quiz.MaxAttempts = *patch.MaxAttempts
+	}
+
+	if patch.AttemptCooldown != nil {
+		quiz.AttemptCooldown = *patch.AttemptCooldown
+	}
+
+	if patch.ScoringPolicy != nil {
+		quiz.ScoringPolicy = *patch.ScoringPolicy
+	}
+
+	if patch.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *patch.ShuffleQuestions
+	}
+
+	if patch.ShuffleOptions != nil {
+		quiz.ShuffleOptions = *patch.ShuffleOptions
+	}
+
+	if patch.FeedbackMode != nil {
+		quiz.FeedbackMode = *patch.FeedbackMode
+	}
+
+	if patch.ClosesAt != nil {
+		quiz.ClosesAt = patch.ClosesAt
+	}
+
+	if patch.ClearClosesAt {
+		quiz.ClosesAt = nil
+	}
+
+	err = models.ValidateFeedback(feedbackMode(quiz), quiz.ClosesAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.IsArchived != nil {
+		quiz.IsArchived = *patch.IsArchived
+	}
+
+	if patch.Tags != nil {
+		quiz.Tags = *patch.Tags
+	}
+
+	// archiving, tags, retake policy and feedback do not change the content of quiz,
+	// so only title, time, marking, passing percentage and shuffling create a new version.
+	if len(diffQuizzes(&currentQuiz, quiz)) > 0 {
+		quiz.Version++
+	}
+
+	err = service.updateQuiz(quiz)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedQuiz := copyQuiz(*quiz)
+	return &updatedQuiz, nil
+}
+
+// Delete will delete quiz. Only creator of the quiz can delete it, and only if no user has attempted it.
+// Attempted quizzes should be archived instead so that results of their attempts remain valid.
+func (service *quizService) Delete(quizID, userID uuid.UUID) error {
+	_, err := service.getOwnedQuiz(quizID, userID)
+	if err != nil {
+		return err
+	}
+
+	// attempts are checked by the database while deleting, so that an attempt started meanwhile is not left without its quiz.
+	err = service.db.DeleteQuiz(quizID)
+	if errors.Is(err, db.ErrRecordInUse) {
+		return ErrQuizHasAttempts
+	}
+
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrQuizNotFound
+	}
+
+	return err
+}
+
+// GetQuizVersions will fetch every version of quiz along with correct answers. Only creator of the quiz can view them.
+func (service *quizService) GetQuizVersions(quizID, userID uuid.UUID) ([]models.Quiz, error) {
+	_, err := service.getOwnedQuiz(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.db.ListQuizVersions(quizID)
+}
+
+// GetQuizVersion will fetch quiz as it was at given version along with correct answers.
+// Only creator of the quiz can view it.
+func (service *quizService) GetQuizVersion(quizID uuid.UUID, version uint32, userID uuid.UUID) (*models.Quiz, error) {
+	_, err := service.getOwnedQuiz(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.getQuizVersion(quizID, version)
+}
+
+// DiffQuizVersions will return changes made to quiz between two versions. Only creator of the quiz can view them.
+func (service *quizService) DiffQuizVersions(quizID uuid.UUID, fromVersion, toVersion uint32, userID uuid.UUID) (*models.QuizDiff, error) {
+	_, err := service.getOwnedQuiz(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromQuiz, err := service.getQuizVersion(quizID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	toQuiz, err := service.getQuizVersion(quizID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QuizDiff{
+		QuizID:      quizID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     diffQuizzes(fromQuiz, toQuiz),
+	}, nil
+}
+
+// getQuizVersion will fetch quiz as it was at given version.
+func (service *quizService) getQuizVersion(quizID uuid.UUID, version uint32) (*models.Quiz, error) {
+	quiz, err := service.db.GetQuizVersion(quizID, version)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizVersionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// getOwnedQuiz will fetch quiz by ID and check that it was created by given user.
+func (service *quizService) getOwnedQuiz(quizID, userID uuid.UUID) (*models.Quiz, error) {
+	quiz, err := service.db.GetQuizByID(quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz.CreatedBy != userID {
+		return nil, ErrNotQuizCreator
+	}
+
+	return quiz, nil
+}
+
+// updateQuiz will store updated quiz in database, unless it was modified after it was read.
+func (service *quizService) updateQuiz(quiz *models.Quiz) error {
+	err := service.db.UpdateQuiz(quiz)
+	if errors.Is(err, db.ErrDuplicateRecord) {
+		return ErrQuizTitleExists
+	}
+
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrQuizNotFound
+	}
+
+	if errors.Is(err, db.ErrVersionConflict) {
+		return ErrQuizModified
+	}
+
+	return err
 }
 
 // checkTitleExist will check if quiz with same title already exists in database.
 func (service *quizService) checkTitleExist(title string) error {
-	for _, quiz := range service.db.Quiz {
-		if strings.EqualFold(strings.ToLower(quiz.Title), strings.ToLower(title)) {
-			return errors.New("quiz with same title already exists")
-		}
+	_, err := service.db.GetQuizByTitle(title)
+	if err == nil {
+		return ErrQuizTitleExists
 	}
 
-	return nil
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil
+	}
+
+	return err
 }
 
 func (service *quizService) assignIDs(quiz *models.Quiz) {
 	quiz.ID = uuid.New()
+	service.assignQuestionIDs(quiz, nil)
+}
+
+// assignQuestionIDs will assign IDs to questions and options of quiz. IDs of questions and options
+// which exist in currentQuiz are kept, every other question and option gets a new ID.
+func (service *quizService) assignQuestionIDs(quiz, currentQuiz *models.Quiz) {
+	currentOptions := map[uuid.UUID]map[uuid.UUID]bool{}
+
+	if currentQuiz != nil {
+		for _, question := range currentQuiz.Questions {
+			currentOptions[question.ID] = map[uuid.UUID]bool{}
+
+			for _, option := range question.Options {
+				currentOptions[question.ID][option.ID] = true
+			}
+		}
+	}
 
 	for i := range quiz.Questions {
-		quiz.Questions[i].ID = uuid.New()
+		options, ok := currentOptions[quiz.Questions[i].ID]
+		if !ok {
+			quiz.Questions[i].ID = uuid.New()
+		}
+
 		quiz.Questions[i].QuizID = quiz.ID
 
 		for j := range quiz.Questions[i].Options {
-			quiz.Questions[i].Options[j].ID = uuid.New()
+			if !options[quiz.Questions[i].Options[j].ID] {
+				quiz.Questions[i].Options[j].ID = uuid.New()
+			}
+
 			quiz.Questions[i].Options[j].QuestionID = quiz.Questions[i].ID
 		}
 	}
 }
 
+// resolveBankQuestions will copy text, type, points, media, options and answers of bank question into every question of quiz
+// referring to question bank. Only questions created by given user can be used, and each of them only once per quiz.
+// IDs of options are copied from the bank so that updating the quiz again does not change them.
+func (service *quizService) resolveBankQuestions(quiz *models.Quiz, userID uuid.UUID) error {
+	used := map[uuid.UUID]bool{}
+
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		if question.BankQuestionID == nil {
+			continue
+		}
+
+		if used[*question.BankQuestionID] {
+			return ErrBankQuestionReused
+		}
+
+		used[*question.BankQuestionID] = true
+
+		bankQuestion, err := service.db.GetBankQuestionByID(*question.BankQuestionID)
+		if errors.Is(err, db.ErrRecordNotFound) || (err == nil && bankQuestion.CreatedBy != userID) {
+			return ErrBankQuestionNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		question.Text = bankQuestion.Text
+		question.Type = bankQuestion.Type
+		question.Points = bankQuestion.Points
+		question.Explanation = bankQuestion.Explanation
+		question.MediaIDs = bankQuestion.MediaIDs
+		question.AcceptedAnswers = bankQuestion.AcceptedAnswers
+		question.NumericAnswer = bankQuestion.NumericAnswer
+		question.Tolerance = bankQuestion.Tolerance
+		question.Options = bankQuestion.Options
+
+		for j := range question.Options {
+			question.Options[j].QuestionID = question.ID
+		}
+	}
+
+	return nil
+}
+
+// renderQuestions will render Markdown of every question of quiz to HTML.
+func renderQuestions(quiz *models.Quiz) {
+	for i := range quiz.Questions {
+		quiz.Questions[i].RenderHTML()
+	}
+}
+
 func copyQuiz(q models.Quiz) models.Quiz {
 	var questions []models.Question
 
@@ -96,13 +481,81 @@ func copyQuiz(q models.Quiz) models.Quiz {
 	}
 
 	return models.Quiz{
-		ID:        q.ID,
-		Title:     q.Title,
-		MaxTime:   q.MaxTime,
-		Questions: questions,
+		ID:                q.ID,
+		Title:             q.Title,
+		MaxTime:           q.MaxTime,
+		NegativeMarking:   q.NegativeMarking,
+		PassingPercentage: q.PassingPercentage,
+		MaxAttempts:       q.MaxAttempts,
+		AttemptCooldown:   q.AttemptCooldown,
+		ScoringPolicy:     q.ScoringPolicy,
+		ShuffleQuestions:  q.ShuffleQuestions,
+		ShuffleOptions:    q.ShuffleOptions,
+		FeedbackMode:      q.FeedbackMode,
+		ClosesAt:          q.ClosesAt,
+		CreatedBy:         q.CreatedBy,
+		CreatedAt:         q.CreatedAt,
+		IsArchived:        q.IsArchived,
+		Version:           q.Version,
+		Revision:          q.Revision,
+		Tags:              q.Tags,
+		Pools:             q.Pools,
+		Questions:         questions,
+	}
+}
+
+// summarizeQuiz will return details of quiz shown when listing quizzes.
+func summarizeQuiz(q models.Quiz) models.QuizSummary {
+	return models.QuizSummary{
+		ID:            q.ID,
+		Title:         q.Title,
+		MaxTime:       q.MaxTime,
+		CreatedBy:     q.CreatedBy,
+		CreatedAt:     q.CreatedAt,
+		IsArchived:    q.IsArchived,
+		Version:       q.Version,
+		Tags:          q.Tags,
+		QuestionCount: len(q.Questions),
 	}
 }
 
+// quizCursor is the content of cursor of a page of quizzes. The position of the last quiz of previous page is kept
+// instead of its ID, so the next page does not depend on that quiz still existing or being unchanged.
+type quizCursor struct {
+	SortBy   string              `json:"sortBy"`
+	Order    string              `json:"order"`
+	Position models.QuizPosition `json:"position"`
+}
+
+// encodeCursor will encode position of quiz, the last quiz of a page, as cursor of the next page.
+func encodeCursor(quiz *models.Quiz, query *models.QuizQuery) string {
+	data, _ := json.Marshal(quizCursor{
+		SortBy:   query.SortBy,
+		Order:    query.Order,
+		Position: models.PositionOf(quiz, query.SortBy),
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor will decode position of the quiz encoded in cursor. Cursor of a list sorted differently than query
+// is not valid.
+func decodeCursor(cursor string, query *models.QuizQuery) (*models.QuizPosition, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	decoded := quizCursor{}
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil || decoded.SortBy != query.SortBy || decoded.Order != query.Order || decoded.Position.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &decoded.Position, nil
+}
+
 func copyQuestion(q models.Question) models.Question {
 	var options []models.Option
 
@@ -110,11 +563,19 @@ func copyQuestion(q models.Question) models.Question {
 		options = append(options, copyOption(option))
 	}
 
+	// accepted and numeric answers are not copied, same as correct options.
 	return models.Question{
-		ID:      q.ID,
-		QuizID:  q.QuizID,
-		Text:    q.Text,
-		Options: options,
+		ID:       q.ID,
+		QuizID:   q.QuizID,
+		Text:     q.Text,
+		TextHTML: q.TextHTML,
+		Type:     q.Type,
+		Points:   q.Points,
+		Pool:     q.Pool,
+		MediaIDs: q.MediaIDs,
+		Options:  options,
+
+		BankQuestionID: q.BankQuestionID,
 	}
 }
 
@@ -123,6 +584,8 @@ func copyOption(o models.Option) models.Option {
 		ID:         o.ID,
 		QuestionID: o.QuestionID,
 		Answer:     o.Answer,
+		AnswerHTML: o.AnswerHTML,
+		MediaIDs:   o.MediaIDs,
 		IsCorrect:  nil,
 	}
 }
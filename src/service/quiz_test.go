@@ -1,7 +1,11 @@
 package service
 
 import (
+	"encoding/json"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shaileshhb/quiz/src/db"
@@ -11,134 +15,637 @@ import (
 
 // TestDuplicateCreate will test for duplicate quiz title creation.
 func TestDuplicateCreate(t *testing.T) {
-	database := db.NewDatabase()
-	quizService := NewQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
 
-	quizOne := models.Quiz{
-		Title:   "Quiz Title 1",
-		MaxTime: 10,
-		Questions: []models.Question{
-			{
-				Text: "Question 1",
-				Options: []models.Option{
-					{
-						Answer: "Answer 1",
+		quizOne := models.Quiz{
+			Title:   "Quiz Title 1",
+			MaxTime: 10,
+			Questions: []models.Question{
+				{
+					Text: "Question 1",
+					Options: []models.Option{
+						{
+							Answer: "Answer 1",
+						},
 					},
 				},
 			},
-		},
-	}
+		}
 
-	quizTwo := models.Quiz{
-		Title:   "Quiz Title 1",
-		MaxTime: 10,
-		Questions: []models.Question{
-			{
-				Text: "Question 1",
-				Options: []models.Option{
-					{
-						Answer: "Answer 1",
+		quizTwo := models.Quiz{
+			Title:   "Quiz Title 1",
+			MaxTime: 10,
+			Questions: []models.Question{
+				{
+					Text: "Question 1",
+					Options: []models.Option{
+						{
+							Answer: "Answer 1",
+						},
 					},
 				},
 			},
-		},
-	}
+		}
 
-	quizService.Create(&quizOne)
+		quizService.Create(&quizOne)
 
-	err := quizService.Create(&quizTwo)
+		err := quizService.Create(&quizTwo)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "quiz with same title already exists", err.Error())
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz with same title already exists", err.Error())
+	})
 }
 
 // TestDefaultQuizTime will test for default quiz time if not provided.
 func TestDefaultQuizTime(t *testing.T) {
-	database := db.NewDatabase()
-	quizService := NewQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
 
-	quizOne := models.Quiz{
-		Title: "Quiz Title 1",
-		Questions: []models.Question{
-			{
-				Text: "Question 1",
-				Options: []models.Option{
-					{
-						Answer: "Answer 1",
+		quizOne := models.Quiz{
+			Title: "Quiz Title 1",
+			Questions: []models.Question{
+				{
+					Text: "Question 1",
+					Options: []models.Option{
+						{
+							Answer: "Answer 1",
+						},
 					},
 				},
 			},
-		},
-	}
+		}
 
-	err := quizService.Create(&quizOne)
+		err := quizService.Create(&quizOne)
 
-	assert.Nil(t, err)
-	assert.Equal(t, uint64(2), quizOne.MaxTime)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), quizOne.MaxTime)
+	})
 }
 
 // TestCreateAssignID will test for assigning unique IDs to quiz.
 func TestCreateAssignID(t *testing.T) {
-	database := db.NewDatabase()
-	quizService := NewQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
 
-	quizOne := models.Quiz{
-		Title: "Quiz Title 1",
-		Questions: []models.Question{
-			{
-				Text: "Question 1",
-				Options: []models.Option{
-					{
-						Answer: "Answer 1",
+		quizOne := models.Quiz{
+			Title: "Quiz Title 1",
+			Questions: []models.Question{
+				{
+					Text: "Question 1",
+					Options: []models.Option{
+						{
+							Answer: "Answer 1",
+						},
 					},
 				},
 			},
-		},
-	}
+		}
 
-	totalQuizzes := len(database.Quiz)
+		err := quizService.Create(&quizOne)
+		assert.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, quizOne.ID)
 
-	err := quizService.Create(&quizOne)
-
-	assert.Nil(t, err)
-	assert.Equal(t, len(database.Quiz), totalQuizzes+1)
+		_, err = database.GetQuizByID(quizOne.ID)
+		assert.Nil(t, err)
+	})
 }
 
 // TestGetQuizNotFound will test for not found quiz
 func TestGetQuizNotFound(t *testing.T) {
-	database := db.NewDatabase()
-	quizService := NewQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
 
-	quizOne := models.Quiz{
-		Title: "Quiz Title 1",
-		Questions: []models.Question{
-			{
-				Text: "Question 1",
-				Options: []models.Option{
-					{
-						Answer: "Answer 1",
+		quizOne := models.Quiz{
+			Title: "Quiz Title 1",
+			Questions: []models.Question{
+				{
+					Text: "Question 1",
+					Options: []models.Option{
+						{
+							Answer: "Answer 1",
+						},
 					},
 				},
 			},
-		},
-	}
+		}
 
-	quizID := uuid.New()
-	_ = quizService.Create(&quizOne)
-	_, err := quizService.GetQuiz(quizID)
+		quizID := uuid.New()
+		_ = quizService.Create(&quizOne)
+		_, err := quizService.GetQuiz(quizID)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "quiz not found", err.Error())
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz not found", err.Error())
+		assert.ErrorIs(t, err, ErrQuizNotFound)
+	})
 }
 
 // TestGetQuiz will test for fetch quiz by quizID
 func TestGetQuiz(t *testing.T) {
-	database := db.NewDatabase()
-	quizService := NewQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		quiz, err := quizService.GetQuiz(quizID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, quiz.ID, quizID)
+	})
+}
+
+// TestUpdateQuizByOtherUser will test that only creator of the quiz can update it.
+func TestUpdateQuizByOtherUser(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		quiz := models.Quiz{
+			ID:    quizID,
+			Title: "Updated quiz",
+		}
+
+		err := quizService.Update(&quiz, uuid.New())
+		assert.NotNil(t, err)
+		assert.Equal(t, "only creator of the quiz can modify it", err.Error())
+
+		err = quizService.Delete(quizID, uuid.New())
+		assert.NotNil(t, err)
+		assert.Equal(t, "only creator of the quiz can modify it", err.Error())
+	})
+}
+
+// TestUpdateQuiz will test that quiz is updated and IDs of existing questions are kept.
+func TestUpdateQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quiz := createTestQuiz(t, database, 2)
+		createdBy := quiz.CreatedBy
+
+		questionID := quiz.Questions[0].ID
+		quiz.Title = "Updated quiz"
+		quiz.Questions = append(quiz.Questions[:1], models.Question{Text: "New question"})
+
+		err := quizService.Update(quiz, createdBy)
+		assert.Nil(t, err)
+
+		updatedQuiz, err := quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Updated quiz", updatedQuiz.Title)
+		assert.Equal(t, createdBy, updatedQuiz.CreatedBy)
+		assert.Equal(t, questionID, updatedQuiz.Questions[0].ID)
+		assert.NotEqual(t, uuid.Nil, updatedQuiz.Questions[1].ID)
+
+		_, err = database.GetQuizByTitle("updated QUIZ")
+		assert.Nil(t, err)
+	})
+}
+
+// TestConcurrentQuizUpdates will test that only one of two updates made from the same version is stored,
+// so that the stored quiz is always the same as the snapshot of its version.
+func TestConcurrentQuizUpdates(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quiz := createTestQuiz(t, database, 2)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+
+		for i, title := range []string{"Edited by A", "Edited by B"} {
+			edited, err := database.GetQuizByID(quiz.ID)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			edited.Title = title
+			edited.Version++
+
+			wg.Add(1)
+			go func(i int, edited *models.Quiz) {
+				defer wg.Done()
+				errs[i] = database.UpdateQuiz(edited)
+			}(i, edited)
+		}
+
+		wg.Wait()
+
+		assert.ElementsMatch(t, []error{nil, db.ErrVersionConflict}, errs)
+
+		stored, err := database.GetQuizByID(quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, quiz.Version+1, stored.Version)
+
+		snapshot, err := database.GetQuizVersion(quiz.ID, stored.Version)
+		assert.Nil(t, err)
+		assert.Equal(t, stored.Title, snapshot.Title)
+	})
+}
+
+// TestUpdateArchivedQuiz will test that an update made from the quiz read before it was archived is rejected
+// instead of un-archiving it, even though archiving does not create a new version.
+func TestUpdateArchivedQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		edited, err := database.GetQuizByID(quiz.ID)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		isArchived := true
+		archived, err := quizService.Patch(quiz.ID, &models.QuizPatch{IsArchived: &isArchived}, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, quiz.Version, archived.Version)
+		assert.Equal(t, edited.Revision+1, archived.Revision)
+
+		edited.Title = "Edited before archive"
+		edited.Version++
+
+		err = database.UpdateQuiz(edited)
+		assert.Equal(t, db.ErrVersionConflict, err)
+
+		stored, err := quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.True(t, stored.IsArchived)
+		assert.Equal(t, quiz.Title, stored.Title)
+	})
+}
+
+// TestArchiveQuiz will test that archived quiz can not be attempted.
+func TestArchiveQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		isArchived := true
+
+		quiz, err := quizService.Patch(quizID, &models.QuizPatch{IsArchived: &isArchived}, userID)
+		assert.Nil(t, err)
+		assert.True(t, quiz.IsArchived)
+
+		err = NewUserQuizService(database).StartQuiz(&models.UserQuizAttempts{UserID: userID, QuizID: quizID})
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz is archived and cannot be attempted", err.Error())
+	})
+}
+
+// TestReopenClosedQuiz will test that close time of quiz can be removed with a patch.
+func TestReopenClosedQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		userQuizService := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 1)
+
+		closesAt := time.Now().Add(-time.Hour)
+		_, err := quizService.Patch(quiz.ID, &models.QuizPatch{ClosesAt: &closesAt}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		err = userQuizService.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+		assert.Equal(t, "quiz has closed and cannot be attempted", err.Error())
+
+		patch := &models.QuizPatch{ClosesAt: &closesAt, ClearClosesAt: true}
+		assert.NotNil(t, patch.Validate())
+
+		// a null close time is same as an omitted one, so it does not reopen the quiz.
+		patch = &models.QuizPatch{}
+		err = json.Unmarshal([]byte(`{"closesAt": null, "clearClosesAt": true}`), patch)
+		assert.Nil(t, err)
+		assert.Nil(t, patch.Validate())
+
+		updatedQuiz, err := quizService.Patch(quiz.ID, patch, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Nil(t, updatedQuiz.ClosesAt)
+
+		err = userQuizService.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+		assert.Nil(t, err)
+
+		// feedback revealed after quiz closes needs a close time.
+		feedbackMode := models.FeedbackAfterClose
+		closesAt = time.Now().Add(time.Hour)
+		_, err = quizService.Patch(quiz.ID, &models.QuizPatch{FeedbackMode: &feedbackMode, ClosesAt: &closesAt}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		_, err = quizService.Patch(quiz.ID, &models.QuizPatch{ClearClosesAt: true}, quiz.CreatedBy)
+		assert.Equal(t, "closes at must be specified when feedback is revealed after quiz closes", err.Error())
+	})
+}
+
+// TestDeleteAttemptedQuiz will test that quiz which has been attempted can not be deleted.
+func TestDeleteAttemptedQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		err := NewUserQuizService(database).StartQuiz(&models.UserQuizAttempts{UserID: userID, QuizID: quizID})
+		assert.Nil(t, err)
+
+		err = quizService.Delete(quizID, userID)
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz has been attempted by users, archive it instead", err.Error())
+	})
+}
+
+// TestConcurrentDeleteAndStartQuiz will test that a quiz is either deleted or attempted when both are done concurrently,
+// so that an attempt is never left without its quiz.
+func TestConcurrentDeleteAndStartQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		userQuizService := NewUserQuizService(database)
+
+		for i := 0; i < 20; i++ {
+			quiz := createTestQuiz(t, database, 1)
+
+			var wg sync.WaitGroup
+			var deleteErr, startErr error
+
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				deleteErr = quizService.Delete(quiz.ID, quiz.CreatedBy)
+			}()
+			go func() {
+				defer wg.Done()
+				startErr = userQuizService.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+			}()
+			wg.Wait()
+
+			totalAttempts, err := database.CountAttemptsByQuiz(quiz.ID)
+			assert.Nil(t, err)
+
+			if deleteErr == nil {
+				assert.Equal(t, ErrQuizNotFound, startErr)
+				assert.Equal(t, 0, totalAttempts)
+			} else {
+				assert.Equal(t, ErrQuizHasAttempts, deleteErr)
+				assert.Nil(t, startErr)
+				assert.Equal(t, 1, totalAttempts)
+			}
+		}
+	})
+}
+
+// TestDeleteQuiz will test deleting quiz which has not been attempted.
+func TestDeleteQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		err := quizService.Delete(quizID, userID)
+		assert.Nil(t, err)
+
+		_, err = quizService.GetQuiz(quizID)
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz not found", err.Error())
+
+		_, err = database.GetQuizByTitle("Sample Quiz")
+		assert.Equal(t, db.ErrRecordNotFound, err)
+	})
+}
+
+// TestDiffQuizVersions will test changes reported between two versions of quiz.
+func TestDiffQuizVersions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		trueValue := true
+		removedQuestionID := quiz.Questions[1].ID
+		optionID := quiz.Questions[0].Options[1].ID
+
+		updatedQuiz := *quiz
+		updatedQuiz.Title = "Updated quiz"
+		updatedQuiz.Questions = []models.Question{quiz.Questions[0]}
+		updatedQuiz.Questions[0].Options[1].IsCorrect = &trueValue
+
+		err := quizService.Update(&updatedQuiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		versions, err := quizService.GetQuizVersions(quiz.ID, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(versions))
+		assert.Equal(t, 2, len(versions[0].Questions))
+
+		diff, err := quizService.DiffQuizVersions(quiz.ID, 1, 2, quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, []models.QuizChange{
+			{Type: models.ChangeModified, Field: "title", From: quiz.Title, To: "Updated quiz"},
+			{Type: models.ChangeModified, Field: "option.isCorrect", QuestionID: &quiz.Questions[0].ID, OptionID: &optionID, From: false, To: true},
+			{Type: models.ChangeRemoved, Field: "question", QuestionID: &removedQuestionID, From: "Question"},
+		}, diff.Changes)
+
+		_, err = quizService.DiffQuizVersions(quiz.ID, 1, 2, uuid.New())
+		assert.NotNil(t, err)
+	})
+}
+
+// TestReorderQuizQuestions will test that moving questions and options creates a new version of quiz.
+func TestReorderQuizQuestions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+		firstID, secondID := quiz.Questions[0].ID, quiz.Questions[1].ID
+
+		updatedQuiz := *quiz
+		updatedQuiz.Questions = []models.Question{quiz.Questions[1], quiz.Questions[0]}
+
+		err := quizService.Update(&updatedQuiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(2), updatedQuiz.Version)
+
+		diff, err := quizService.DiffQuizVersions(quiz.ID, 1, 2, quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, []models.QuizChange{
+			{Type: models.ChangeModified, Field: "questionOrder", From: []uuid.UUID{firstID, secondID}, To: []uuid.UUID{secondID, firstID}},
+		}, diff.Changes)
+
+		version, err := quizService.GetQuizVersion(quiz.ID, 2, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, secondID, version.Questions[0].ID)
+
+		options := quiz.Questions[0].Options
+		reorderedQuiz := updatedQuiz
+		reorderedQuiz.Questions = []models.Question{updatedQuiz.Questions[0], updatedQuiz.Questions[1]}
+		reorderedQuiz.Questions[1].Options = []models.Option{options[1], options[0], options[2], options[3]}
+
+		err = quizService.Update(&reorderedQuiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(3), reorderedQuiz.Version)
+
+		diff, err = quizService.DiffQuizVersions(quiz.ID, 2, 3, quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, []models.QuizChange{
+			{
+				Type: models.ChangeModified, Field: "question.optionOrder", QuestionID: &firstID,
+				From: []uuid.UUID{options[0].ID, options[1].ID, options[2].ID, options[3].ID},
+				To:   []uuid.UUID{options[1].ID, options[0].ID, options[2].ID, options[3].ID},
+			},
+		}, diff.Changes)
+	})
+}
+
+// TestListQuizzes will test filtering, sorting and pagination of quizzes.
+func TestListQuizzes(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		tags := []string{"Listing"}
+		trueValue := true
+		falseValue := false
+
+		var quizzes []*models.Quiz
+		for i := 0; i < 5; i++ {
+			quiz := createTestQuiz(t, database, i+1)
+			_, err := quizService.Patch(quiz.ID, &models.QuizPatch{Tags: &tags}, quiz.CreatedBy)
+			assert.Nil(t, err)
+
+			quizzes = append(quizzes, quiz)
+		}
+
+		_, err := quizService.Patch(quizzes[4].ID, &models.QuizPatch{IsArchived: &trueValue}, quizzes[4].CreatedBy)
+		assert.Nil(t, err)
+
+		err = NewUserQuizService(database).StartQuiz(&models.UserQuizAttempts{
+			UserID: quizzes[1].CreatedBy,
+			QuizID: quizzes[1].ID,
+		})
+		assert.Nil(t, err)
+
+		list := func(query models.QuizQuery) []uuid.UUID {
+			var quizIDs []uuid.UUID
+			cursor := ""
+
+			for {
+				err := query.Validate()
+				if !assert.Nil(t, err) {
+					return nil
+				}
+
+				quizList, err := quizService.List(&query, cursor)
+				if !assert.Nil(t, err) {
+					return nil
+				}
+
+				for _, quiz := range quizList.Quizzes {
+					quizIDs = append(quizIDs, quiz.ID)
+				}
+
+				if quizList.NextCursor == "" {
+					// total counts quizzes of every page.
+					assert.Equal(t, quizList.Total, len(quizIDs))
+					return quizIDs
+				}
+
+				cursor = quizList.NextCursor
+			}
+		}
+
+		assert.Equal(t, []uuid.UUID{quizzes[4].ID, quizzes[3].ID, quizzes[2].ID, quizzes[1].ID, quizzes[0].ID},
+			list(models.QuizQuery{Tag: "listing", SortBy: models.SortByQuestionCount, Order: models.OrderDesc, Limit: 2}))
+
+		assert.Equal(t, []uuid.UUID{quizzes[0].ID, quizzes[1].ID, quizzes[2].ID, quizzes[3].ID},
+			list(models.QuizQuery{Tag: "listing", IsArchived: &falseValue, SortBy: models.SortByQuestionCount}))
+
+		assert.Equal(t, []uuid.UUID{quizzes[1].ID},
+			list(models.QuizQuery{Tag: "listing", AttemptedBy: &quizzes[1].CreatedBy, HasAttempted: &trueValue}))
+
+		assert.Equal(t, 4, len(list(models.QuizQuery{Tag: "listing", AttemptedBy: &quizzes[1].CreatedBy, HasAttempted: &falseValue})))
+
+		assert.Equal(t, []uuid.UUID{quizzes[2].ID},
+			list(models.QuizQuery{Search: strings.ToUpper(quizzes[2].Title[5:])}))
+
+		_, err = quizService.List(&models.QuizQuery{Limit: 2}, "invalid")
+		assert.NotNil(t, err)
+		assert.Equal(t, "invalid cursor", err.Error())
+	})
+}
+
+// TestListQuizzesAfterChangedQuiz will test that the next page does not depend on the last quiz of previous page
+// still existing or being unchanged.
+func TestListQuizzesAfterChangedQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		prefix := "Cursor " + uuid.NewString()[:8]
+
+		var quizzes []*models.Quiz
+		for _, suffix := range []string{" A", " B", " C", " D"} {
+			quiz := createTestQuiz(t, database, 1)
+			title := prefix + suffix
+			_, err := quizService.Patch(quiz.ID, &models.QuizPatch{Title: &title}, quiz.CreatedBy)
+			assert.Nil(t, err)
+
+			quizzes = append(quizzes, quiz)
+		}
+
+		query := models.QuizQuery{Search: prefix, SortBy: models.SortByTitle, Limit: 2}
+		err := query.Validate()
+		assert.Nil(t, err)
+
+		firstPage, err := quizService.List(&query, "")
+		assert.Nil(t, err)
+		assert.Equal(t, quizzes[1].ID, firstPage.Quizzes[1].ID)
+
+		// renaming the last quiz of the page neither repeats nor skips quizzes.
+		title := prefix + " Z"
+		_, err = quizService.Patch(quizzes[1].ID, &models.QuizPatch{Title: &title}, quizzes[1].CreatedBy)
+		assert.Nil(t, err)
+
+		nextPage, err := quizService.List(&query, firstPage.NextCursor)
+		assert.Nil(t, err)
+		assert.Equal(t, []uuid.UUID{quizzes[2].ID, quizzes[3].ID}, []uuid.UUID{nextPage.Quizzes[0].ID, nextPage.Quizzes[1].ID})
+
+		err = quizService.Delete(quizzes[1].ID, quizzes[1].CreatedBy)
+		assert.Nil(t, err)
+
+		nextPage, err = quizService.List(&query, firstPage.NextCursor)
+		assert.Nil(t, err)
+		assert.Equal(t, []uuid.UUID{quizzes[2].ID, quizzes[3].ID}, []uuid.UUID{nextPage.Quizzes[0].ID, nextPage.Quizzes[1].ID})
+
+		// cursor can not be used with a different sorting.
+		query.Order = models.OrderDesc
+		_, err = quizService.List(&query, firstPage.NextCursor)
+		assert.Equal(t, ErrInvalidCursor, err)
+	})
+}
+
+// TestRenderQuizMarkdown will test that HTML of questions is rendered by the server, ignoring HTML sent by client.
+func TestRenderQuizMarkdown(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		trueValue := true
+
+		quiz := models.Quiz{
+			Title: "Markdown Quiz",
+			Questions: []models.Question{
+				{
+					Text:     "What's *2 + 2*?",
+					TextHTML: "<script>alert(1)</script>",
+					Options: []models.Option{
+						{Answer: "`4`", AnswerHTML: "<img src=x onerror=alert(1)>", IsCorrect: &trueValue},
+					},
+				},
+			},
+		}
 
-	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
-	quiz, err := quizService.GetQuiz(quizID)
+		err := quizService.Create(&quiz)
+		assert.Nil(t, err)
 
-	assert.Nil(t, err)
-	assert.Equal(t, quiz.ID, quizID)
+		storedQuiz, err := quizService.GetQuiz(quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "What's *2 + 2*?", storedQuiz.Questions[0].Text)
+		assert.Equal(t, "<p>What&#39;s <em>2 + 2</em>?</p>", storedQuiz.Questions[0].TextHTML)
+		assert.Equal(t, "<p><code>4</code></p>", storedQuiz.Questions[0].Options[0].AnswerHTML)
+	})
 }
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/quizformat"
+	"github.com/shaileshhb/quiz/src/storage"
+)
+
+// QuizExportService will consist of service methods that would be implemented by quizExportService
+type QuizExportService interface {
+	Export(quizID uuid.UUID, format string, userID uuid.UUID) (*quizformat.Export, error)
+}
+
+// quizExportService will contain reference to db and storage keeping content of media attached to quizzes.
+type quizExportService struct {
+	db      db.Repository
+	storage storage.FileStorage
+}
+
+// NewQuizExportService will create new instance of quizExportService
+func NewQuizExportService(db db.Repository, storage storage.FileStorage) QuizExportService {
+	return &quizExportService{
+		db:      db,
+		storage: storage,
+	}
+}
+
+// Export will write quiz to a file of given format. Exported files contain correct answers,
+// so only creator of the quiz can export it.
+func (service *quizExportService) Export(quizID uuid.UUID, format string, userID uuid.UUID) (*quizformat.Export, error) {
+	quiz, err := service.db.GetQuizByID(quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz.CreatedBy != userID {
+		return nil, ErrNotQuizCreator.withMessage("only creator of the quiz can export it")
+	}
+
+	var media []quizformat.MediaFile
+
+	if quizformat.IncludesMedia(format) {
+		for _, mediaID := range quiz.MediaIDs() {
+			file, err := service.readMedia(mediaID)
+			if err != nil {
+				return nil, err
+			}
+
+			media = append(media, *file)
+		}
+	}
+
+	export, err := quizformat.Write(format, quiz, media)
+	if err != nil {
+		return nil, InvalidRequest(err)
+	}
+
+	return export, nil
+}
+
+// readMedia will fetch details and content of media.
+func (service *quizExportService) readMedia(mediaID uuid.UUID) (*quizformat.MediaFile, error) {
+	media, err := service.db.GetMediaByID(mediaID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrMediaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := service.storage.Open(mediaID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quizformat.MediaFile{Media: *media, Content: data}, nil
+}
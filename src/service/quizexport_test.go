@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/quizformat"
+	"github.com/shaileshhb/quiz/src/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExportQuiz will test that only creator of the quiz can export it, along with correct answers and media.
+func TestExportQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		mediaStorage, err := storage.NewLocalStorage(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		quizService := NewQuizService(database)
+		exportService := NewQuizExportService(database, mediaStorage)
+		quiz := createTestQuiz(t, database, 2)
+		content := createTestImage(t, 10, 10)
+
+		media, err := NewMediaService(database, mediaStorage).Upload("diagram.png", bytes.NewReader(content), quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		quiz.Questions[1].MediaIDs = append(quiz.Questions[1].MediaIDs, media.ID)
+		err = quizService.Update(quiz, quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		export, err := exportService.Export(quiz.ID, quizformat.FormatJSON, quiz.CreatedBy)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		bundle := quizformat.Bundle{}
+		err = json.Unmarshal(export.Content, &bundle)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, quiz.ID, bundle.Quiz.ID)
+		assert.True(t, *bundle.Quiz.Questions[0].Options[0].IsCorrect)
+		if assert.Len(t, bundle.Media, 1) {
+			assert.Equal(t, media.ID, bundle.Media[0].ID)
+			assert.Equal(t, content, bundle.Media[0].Content)
+		}
+
+		_, err = exportService.Export(quiz.ID, quizformat.FormatJSON, uuid.New())
+		assert.Equal(t, "only creator of the quiz can export it", err.Error())
+		assert.ErrorIs(t, err, ErrNotQuizCreator)
+		assert.ErrorIs(t, err, ErrForbidden)
+
+		_, err = exportService.Export(uuid.New(), quizformat.FormatJSON, quiz.CreatedBy)
+		assert.Equal(t, "quiz not found", err.Error())
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = exportService.Export(quiz.ID, "pdf", quiz.CreatedBy)
+		assert.Equal(t, "format must be one of qti, json or gift", err.Error())
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+}
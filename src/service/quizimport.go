@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/quizformat"
+)
+
+// Import will create quizzes read from a CSV, GIFT or Moodle XML file, owned by given user.
+// Every quiz and question of the file is validated first. If any of them is invalid no quiz is created,
+// and all problems are returned in the report along with the line of the file they were found on.
+// If creating a quiz fails, quizzes created before it are deleted again.
+func (service *quizService) Import(format string, content io.Reader, title string, userID uuid.UUID) (*models.ImportReport, error) {
+	imported, err := quizformat.Read(format, content, title)
+	if err != nil {
+		return nil, InvalidRequest(err)
+	}
+
+	report := &models.ImportReport{
+		Quizzes: []models.QuizSummary{},
+		Errors:  append([]models.ImportError{}, imported.Errors...),
+	}
+
+	for i := range imported.Quizzes {
+		report.Errors = append(report.Errors, service.validateImportedQuiz(&imported.Quizzes[i], userID)...)
+	}
+
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	for i := range imported.Quizzes {
+		quiz := &imported.Quizzes[i].Quiz
+
+		err = service.Create(quiz)
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportError{Quiz: quiz.Title, Error: err.Error()})
+			service.removeImportedQuizzes(report)
+			return report, nil
+		}
+
+		report.Quizzes = append(report.Quizzes, summarizeQuiz(*quiz))
+	}
+
+	return report, nil
+}
+
+// removeImportedQuizzes will delete quizzes of the report which were created before the import failed.
+// Quizzes which can not be deleted, e.g. because they were attempted meanwhile, are kept in the report along with the error.
+func (service *quizService) removeImportedQuizzes(report *models.ImportReport) {
+	created := report.Quizzes
+	report.Quizzes = []models.QuizSummary{}
+
+	for _, quiz := range created {
+		err := service.db.DeleteQuiz(quiz.ID)
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			report.Quizzes = append(report.Quizzes, quiz)
+			report.Errors = append(report.Errors, models.ImportError{
+				Quiz:  quiz.Title,
+				Error: "quiz could not be removed after import failed: " + err.Error(),
+			})
+		}
+	}
+}
+
+// validateImportedQuiz will validate quiz along with every question of it, and return all problems found.
+// Problems of a question are reported on the line of the file where it starts.
+func (service *quizService) validateImportedQuiz(imported *quizformat.ImportedQuiz, userID uuid.UUID) []models.ImportError {
+	quiz := &imported.Quiz
+	quiz.CreatedBy = userID
+
+	var importErrors []models.ImportError
+	var validationErr *models.ValidationError
+
+	err := quiz.Validate()
+	if errors.As(err, &validationErr) {
+		for _, violation := range validationErr.Violations {
+			importErrors = append(importErrors, importViolation(imported, violation))
+		}
+	} else if err != nil {
+		importErrors = append(importErrors, models.ImportError{Quiz: quiz.Title, Error: err.Error()})
+	}
+
+	_, err = service.db.GetQuizByTitle(strings.TrimSpace(quiz.Title))
+	if err == nil {
+		importErrors = append(importErrors, models.ImportError{Quiz: quiz.Title, Error: "quiz with same title already exists"})
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		importErrors = append(importErrors, models.ImportError{Quiz: quiz.Title, Error: err.Error()})
+	}
+
+	return importErrors
+}
+
+// importViolation will map violation of imported quiz onto a problem of the import report,
+// along with the line where the question it belongs to starts.
+func importViolation(imported *quizformat.ImportedQuiz, violation models.Violation) models.ImportError {
+	importError := models.ImportError{Quiz: imported.Quiz.Title, Field: violation.Field, Error: violation.Message}
+
+	var index int
+	if n, _ := fmt.Sscanf(violation.Field, "questions[%d]", &index); n == 1 && index >= 0 && index < len(imported.Lines) {
+		importError.Line = imported.Lines[index]
+	}
+
+	return importError
+}
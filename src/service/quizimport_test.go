@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestImportQuizzes will test that imported quizzes are created only if all of their questions are valid.
+func TestImportQuizzes(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(database)
+		userID := uuid.New()
+
+		content := "quiz,question,option1,option2,correct\n" +
+			"Capitals,Capital of France?,Paris,London,1\n" +
+			"Capitals,Capital of India?,Delhi,,1\n" +
+			"Rivers,,Nile,Amazon,1\n" +
+			"Sample Quiz,Capital of Japan?,Tokyo,Delhi,1\n"
+
+		report, err := quizService.Import("csv", strings.NewReader(content), "Imported", userID)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Empty(t, report.Quizzes)
+		assert.Equal(t, []models.ImportError{
+			{Line: 3, Quiz: "Capitals", Field: "questions[1].options", Error: "question should have between 2 and 10 options"},
+			{Line: 4, Quiz: "Rivers", Field: "questions[0].text", Error: "text must be specified"},
+			{Quiz: "Sample Quiz", Error: "quiz with same title already exists"},
+		}, report.Errors)
+
+		_, err = database.GetQuizByTitle("Capitals")
+		assert.Equal(t, db.ErrRecordNotFound, err)
+
+		content = "quiz,question,option1,option2,correct\n" +
+			"Capitals,Capital of France?,Paris,London,1\n" +
+			",Longest river?,Nile,Amazon,1\n"
+
+		report, err = quizService.Import("csv", strings.NewReader(content), "Imported Quiz", userID)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Empty(t, report.Errors)
+		assert.Len(t, report.Quizzes, 2)
+
+		quiz, err := database.GetQuizByTitle("Imported Quiz")
+		assert.Nil(t, err)
+		assert.Equal(t, userID, quiz.CreatedBy)
+		assert.Equal(t, models.FeedbackImmediate, quiz.FeedbackMode)
+		assert.Equal(t, "<p>Longest river?</p>", quiz.Questions[0].TextHTML)
+
+		_, err = quizService.Import("xlsx", strings.NewReader(content), "Imported", userID)
+		assert.Equal(t, "format must be one of csv, gift or moodle", err.Error())
+	})
+}
+
+// failingCreateRepository will fail to create quiz with given title.
+type failingCreateRepository struct {
+	db.Repository
+	title string
+}
+
+func (repository *failingCreateRepository) CreateQuiz(quiz *models.Quiz) error {
+	if quiz.Title == repository.title {
+		return errors.New("database is locked")
+	}
+
+	return repository.Repository.CreateQuiz(quiz)
+}
+
+// TestImportQuizzesFailingPartway will test that quizzes created before a quiz of the file fails to be created
+// are deleted, so that no quiz of the file is created.
+func TestImportQuizzesFailingPartway(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		quizService := NewQuizService(&failingCreateRepository{Repository: database, title: "Rivers"})
+		userID := uuid.New()
+
+		content := "quiz,question,option1,option2,correct\n" +
+			"Capitals,Capital of France?,Paris,London,1\n" +
+			"Rivers,Longest river?,Nile,Amazon,1\n" +
+			"Mountains,Highest mountain?,Everest,K2,1\n"
+
+		report, err := quizService.Import("csv", strings.NewReader(content), "Imported", userID)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Empty(t, report.Quizzes)
+		assert.Equal(t, []models.ImportError{{Quiz: "Rivers", Error: "database is locked"}}, report.Errors)
+
+		for _, title := range []string{"Capitals", "Rivers", "Mountains"} {
+			_, err = database.GetQuizByTitle(title)
+			assert.Equal(t, db.ErrRecordNotFound, err, title)
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// diffQuizzes will return all changes made to title, time, marking, shuffling, questions and options between two versions of quiz.
+// Questions and options are matched by their ID, moving them to a different position is also a change.
+func diffQuizzes(from, to *models.Quiz) []models.QuizChange {
+	changes := []models.QuizChange{}
+
+	if from.Title != to.Title {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "title", From: from.Title, To: to.Title,
+		})
+	}
+
+	if from.MaxTime != to.MaxTime {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "maxTime", From: from.MaxTime, To: to.MaxTime,
+		})
+	}
+
+	if from.NegativeMarking != to.NegativeMarking {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "negativeMarking", From: from.NegativeMarking, To: to.NegativeMarking,
+		})
+	}
+
+	if from.PassingPercentage != to.PassingPercentage {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "passingPercentage", From: from.PassingPercentage, To: to.PassingPercentage,
+		})
+	}
+
+	// attempts keep using the order of their version, so shuffling also creates a new version.
+	if from.ShuffleQuestions != to.ShuffleQuestions {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "shuffleQuestions", From: from.ShuffleQuestions, To: to.ShuffleQuestions,
+		})
+	}
+
+	if from.ShuffleOptions != to.ShuffleOptions {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "shuffleOptions", From: from.ShuffleOptions, To: to.ShuffleOptions,
+		})
+	}
+
+	if !equalPools(from.Pools, to.Pools) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "pools", From: from.Pools, To: to.Pools,
+		})
+	}
+
+	fromQuestions := map[uuid.UUID]models.Question{}
+	for _, question := range from.Questions {
+		fromQuestions[question.ID] = question
+	}
+
+	toQuestions := map[uuid.UUID]bool{}
+
+	for _, question := range to.Questions {
+		questionID := question.ID
+		toQuestions[questionID] = true
+
+		fromQuestion, ok := fromQuestions[questionID]
+		if !ok {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeAdded, Field: "question", QuestionID: &questionID, To: question.Text,
+			})
+			continue
+		}
+
+		changes = append(changes, diffQuestions(&fromQuestion, &question)...)
+	}
+
+	for _, question := range from.Questions {
+		questionID := question.ID
+
+		if !toQuestions[questionID] {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeRemoved, Field: "question", QuestionID: &questionID, From: question.Text,
+			})
+		}
+	}
+
+	fromOrder, toOrder := keptOrder(questionIDs(from.Questions), questionIDs(to.Questions))
+	if !slices.Equal(fromOrder, toOrder) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "questionOrder", From: fromOrder, To: toOrder,
+		})
+	}
+
+	return changes
+}
+
+// diffQuestions will return changes made to text and options of a question.
+func diffQuestions(from, to *models.Question) []models.QuizChange {
+	changes := []models.QuizChange{}
+	questionID := to.ID
+
+	if from.Text != to.Text {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.text", QuestionID: &questionID, From: from.Text, To: to.Text,
+		})
+	}
+
+	if questionPoints(from) != questionPoints(to) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.points", QuestionID: &questionID,
+			From: questionPoints(from), To: questionPoints(to),
+		})
+	}
+
+	if from.Pool != to.Pool {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.pool", QuestionID: &questionID, From: from.Pool, To: to.Pool,
+		})
+	}
+
+	if from.Explanation != to.Explanation {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.explanation", QuestionID: &questionID,
+			From: from.Explanation, To: to.Explanation,
+		})
+	}
+
+	if !slices.Equal(from.MediaIDs, to.MediaIDs) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.mediaIDs", QuestionID: &questionID,
+			From: from.MediaIDs, To: to.MediaIDs,
+		})
+	}
+
+	if !reflect.DeepEqual(from.BankQuestionID, to.BankQuestionID) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.bankQuestionID", QuestionID: &questionID,
+			From: from.BankQuestionID, To: to.BankQuestionID,
+		})
+	}
+
+	if questionType(from) != questionType(to) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.type", QuestionID: &questionID,
+			From: questionType(from), To: questionType(to),
+		})
+	}
+
+	if !reflect.DeepEqual(from.AcceptedAnswers, to.AcceptedAnswers) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.acceptedAnswers", QuestionID: &questionID,
+			From: from.AcceptedAnswers, To: to.AcceptedAnswers,
+		})
+	}
+
+	if !reflect.DeepEqual(from.NumericAnswer, to.NumericAnswer) || from.Tolerance != to.Tolerance {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.numericAnswer", QuestionID: &questionID,
+			From: from.NumericAnswer, To: to.NumericAnswer,
+		})
+	}
+
+	fromOptions := map[uuid.UUID]models.Option{}
+	for _, option := range from.Options {
+		fromOptions[option.ID] = option
+	}
+
+	toOptions := map[uuid.UUID]bool{}
+
+	for _, option := range to.Options {
+		optionID := option.ID
+		toOptions[optionID] = true
+
+		fromOption, ok := fromOptions[optionID]
+		if !ok {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeAdded, Field: "option", QuestionID: &questionID, OptionID: &optionID, To: option.Answer,
+			})
+			continue
+		}
+
+		if fromOption.Answer != option.Answer {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeModified, Field: "option.answer", QuestionID: &questionID, OptionID: &optionID,
+				From: fromOption.Answer, To: option.Answer,
+			})
+		}
+
+		if fromOption.Feedback != option.Feedback {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeModified, Field: "option.feedback", QuestionID: &questionID, OptionID: &optionID,
+				From: fromOption.Feedback, To: option.Feedback,
+			})
+		}
+
+		if !slices.Equal(fromOption.MediaIDs, option.MediaIDs) {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeModified, Field: "option.mediaIDs", QuestionID: &questionID, OptionID: &optionID,
+				From: fromOption.MediaIDs, To: option.MediaIDs,
+			})
+		}
+
+		if isCorrect(fromOption) != isCorrect(option) {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeModified, Field: "option.isCorrect", QuestionID: &questionID, OptionID: &optionID,
+				From: isCorrect(fromOption), To: isCorrect(option),
+			})
+		}
+	}
+
+	for _, option := range from.Options {
+		optionID := option.ID
+
+		if !toOptions[optionID] {
+			changes = append(changes, models.QuizChange{
+				Type: models.ChangeRemoved, Field: "option", QuestionID: &questionID, OptionID: &optionID, From: option.Answer,
+			})
+		}
+	}
+
+	fromOrder, toOrder := keptOrder(optionIDs(from.Options), optionIDs(to.Options))
+	if !slices.Equal(fromOrder, toOrder) {
+		changes = append(changes, models.QuizChange{
+			Type: models.ChangeModified, Field: "question.optionOrder", QuestionID: &questionID,
+			From: fromOrder, To: toOrder,
+		})
+	}
+
+	return changes
+}
+
+// keptOrder will return IDs present in both lists, in the order of each list. Added and removed questions or options
+// are reported on their own, so only IDs which are kept are compared to find if they were moved.
+func keptOrder(from, to []uuid.UUID) ([]uuid.UUID, []uuid.UUID) {
+	keptFrom := []uuid.UUID{}
+	keptTo := []uuid.UUID{}
+
+	for _, id := range from {
+		if slices.Contains(to, id) {
+			keptFrom = append(keptFrom, id)
+		}
+	}
+
+	for _, id := range to {
+		if slices.Contains(from, id) {
+			keptTo = append(keptTo, id)
+		}
+	}
+
+	return keptFrom, keptTo
+}
+
+// questionIDs will return IDs of questions in their order.
+func questionIDs(questions []models.Question) []uuid.UUID {
+	ids := make([]uuid.UUID, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].ID
+	}
+
+	return ids
+}
+
+// optionIDs will return IDs of options in their order.
+func optionIDs(options []models.Option) []uuid.UUID {
+	ids := make([]uuid.UUID, len(options))
+	for i := range options {
+		ids[i] = options[i].ID
+	}
+
+	return ids
+}
+
+// questionType will return type of question, questions without a type are single choice.
+func questionType(question *models.Question) string {
+	if question.Type == "" {
+		return models.QuestionTypeSingleChoice
+	}
+
+	return question.Type
+}
+
+// isCorrect will return whether option is marked as correct.
+func isCorrect(option models.Option) bool {
+	return option.IsCorrect != nil && *option.IsCorrect
+}
+
+// equalPools will check if both quizzes have same pools in same order, quizzes without pools are equal.
+func equalPools(from, to []models.QuestionPool) bool {
+	if len(from) != len(to) {
+		return false
+	}
+
+	for i := range from {
+		if from[i] != to[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// attemptsAllowed will return number of times a user can attempt quiz.
+// Quizzes created before retakes were introduced can be attempted only once.
+func attemptsAllowed(quiz *models.Quiz) uint32 {
+	if quiz.MaxAttempts == 0 {
+		return 1
+	}
+
+	return quiz.MaxAttempts
+}
+
+// scoringPolicy will return policy deciding which attempts count towards final result, best attempt counts by default.
+func scoringPolicy(quiz *models.Quiz) string {
+	if quiz.ScoringPolicy == "" {
+		return models.ScoringPolicyBest
+	}
+
+	return quiz.ScoringPolicy
+}
+
+// nextAttemptAt will return time after which next attempt can be started once given attempt has ended.
+// It returns nil if quiz has no cooldown between attempts.
+func nextAttemptAt(attempt *models.UserQuizAttempts, quiz *models.Quiz) *time.Time {
+	if quiz.AttemptCooldown == 0 || attempt.EndedAt == nil {
+		return nil
+	}
+
+	next := attempt.EndedAt.Add(time.Duration(quiz.AttemptCooldown * uint64(time.Minute)))
+	return &next
+}
+
+// summarizeAttempts will return all attempts of a user along with their final result decided by scoring policy of quiz.
+// Only attempts which have ended are counted, attempts are expected to be sorted by attempt number.
+func summarizeAttempts(attempts []models.UserQuizAttempts, quiz *models.Quiz, now time.Time) *models.UserQuizAttemptList {
+	attemptList := &models.UserQuizAttemptList{
+		Attempts:      attempts,
+		ScoringPolicy: scoringPolicy(quiz),
+	}
+
+	if allowed := attemptsAllowed(quiz); uint32(len(attempts)) < allowed {
+		attemptList.AttemptsRemaining = allowed - uint32(len(attempts))
+	}
+
+	var ended []*models.UserQuizAttempts
+	for i := range attempts {
+		if attempts[i].EndedAt != nil {
+			ended = append(ended, &attempts[i])
+		}
+	}
+
+	if len(ended) == 0 {
+		return attemptList
+	}
+
+	// result is judged against passing percentage pinned on the counted attempt when it ended, so that editing
+	// the quiz does not change results of finished attempts. Average is judged against the latest ended attempt.
+	counted := ended[len(ended)-1]
+
+	switch attemptList.ScoringPolicy {
+	case models.ScoringPolicyLatest:
+		attemptList.CountedAttemptID = &counted.ID
+		attemptList.Percentage = counted.Percentage
+
+	case models.ScoringPolicyAverage:
+		total := 0.0
+		for _, attempt := range ended {
+			total += attempt.Percentage
+		}
+		attemptList.Percentage = math.Round(total/float64(len(ended))*100) / 100
+
+	default:
+		counted = ended[0]
+		for _, attempt := range ended[1:] {
+			if attempt.Percentage > counted.Percentage {
+				counted = attempt
+			}
+		}
+		attemptList.CountedAttemptID = &counted.ID
+		attemptList.Percentage = counted.Percentage
+	}
+
+	attemptList.Outcome = models.OutcomeFailed
+	if attemptList.Percentage >= counted.PassingPercentage {
+		attemptList.Outcome = models.OutcomePassed
+	}
+
+	latest := &attempts[len(attempts)-1]
+	if next := nextAttemptAt(latest, quiz); attemptList.AttemptsRemaining > 0 && next != nil && now.Before(*next) {
+		attemptList.NextAttemptAt = next
+	}
+
+	return attemptList
+}
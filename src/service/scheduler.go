@@ -0,0 +1,73 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultExpiryInterval is how often ExpiryScheduler ends attempts whose maximum time has exceeded.
+const DefaultExpiryInterval = 5 * time.Second
+
+// ExpiryScheduler will periodically end attempts whose maximum time has exceeded,
+// so that they are finalized even if the user never submits an answer or views the results.
+type ExpiryScheduler struct {
+	service  UserQuizService
+	interval time.Duration
+	log      zerolog.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewExpiryScheduler will create new instance of ExpiryScheduler which runs every interval once started.
+func NewExpiryScheduler(service UserQuizService, interval time.Duration, log zerolog.Logger) *ExpiryScheduler {
+	return &ExpiryScheduler{
+		service:  service,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Start will start ending expired attempts in background until Stop is called.
+func (scheduler *ExpiryScheduler) Start() {
+	scheduler.stop = make(chan struct{})
+	scheduler.wg.Add(1)
+
+	go func() {
+		defer scheduler.wg.Done()
+
+		ticker := time.NewTicker(scheduler.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-scheduler.stop:
+				return
+			case <-ticker.C:
+				scheduler.run()
+			}
+		}
+	}()
+
+	scheduler.log.Info().Dur("interval", scheduler.interval).Msg("Attempt expiry scheduler started")
+}
+
+// Stop will stop the scheduler and wait for the running expiry to complete.
+func (scheduler *ExpiryScheduler) Stop() {
+	close(scheduler.stop)
+	scheduler.wg.Wait()
+}
+
+// run will end all expired attempts once.
+func (scheduler *ExpiryScheduler) run() {
+	totalExpired, err := scheduler.service.ExpireAttempts()
+	if err != nil {
+		scheduler.log.Error().Err(err).Msg("Error expiring attempts")
+	}
+
+	if totalExpired > 0 {
+		scheduler.log.Info().Int("attempts", totalExpired).Msg("Expired attempts")
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+	"github.com/shaileshhb/quiz/src/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExpireAttempts will test that attempts are ended once maximum time of quiz has exceeded.
+func TestExpireAttempts(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		clock := newFakeClock()
+
+		var mu sync.Mutex
+		outcomes := map[string]int{}
+
+		serv := NewUserQuizService(database, WithClock(clock), WithAttemptEndedHook(func(attempt models.UserQuizAttempts) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			outcomes[attempt.Outcome]++
+		}))
+
+		quiz := createTestQuiz(t, database, 2)
+		userTwo, err := database.GetUserByUsername("usertwo")
+		assert.Nil(t, err)
+
+		answered := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		assert.Nil(t, serv.StartQuiz(&answered))
+		assert.Equal(t, clock.Now().Add(2*time.Minute), *answered.ExpiresAt)
+
+		unanswered := models.UserQuizAttempts{UserID: userTwo.ID, QuizID: quiz.ID}
+		assert.Nil(t, serv.StartQuiz(&unanswered))
+
+		_, err = serv.SubmitAnswer(&models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: answered.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			SelectedOptionID:  quiz.Questions[0].Options[0].ID,
+		})
+		assert.Nil(t, err)
+
+		clock.Advance(time.Minute)
+
+		totalExpired, err := serv.ExpireAttempts()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, totalExpired)
+
+		clock.Advance(2 * time.Minute)
+
+		totalExpired, err = serv.ExpireAttempts()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, totalExpired)
+		assert.Equal(t, map[string]int{models.OutcomeTimedOut: 1, models.OutcomeAbandoned: 1}, outcomes)
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.OutcomeTimedOut, result.Outcome)
+		assert.True(t, answered.ExpiresAt.Equal(*result.EndedAt))
+
+		totalExpired, err = serv.ExpireAttempts()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, totalExpired)
+	})
+}
+
+// TestExpiryScheduler will test that scheduler ends expired attempts in background.
+func TestExpiryScheduler(t *testing.T) {
+	database := db.NewDatabase()
+	clock := newFakeClock()
+	ended := make(chan models.UserQuizAttempts, 1)
+
+	serv := NewUserQuizService(database, WithClock(clock), WithAttemptEndedHook(func(attempt models.UserQuizAttempts) {
+		ended <- attempt
+	}))
+
+	quiz := createTestQuiz(t, database, 1)
+
+	attempt := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+	assert.Nil(t, serv.StartQuiz(&attempt))
+
+	scheduler := NewExpiryScheduler(serv, 10*time.Millisecond, log.InitializeLogger())
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	clock.Advance(3 * time.Minute)
+
+	select {
+	case endedAttempt := <-ended:
+		assert.Equal(t, attempt.ID, endedAttempt.ID)
+		assert.Equal(t, models.OutcomeAbandoned, endedAttempt.Outcome)
+	case <-time.After(5 * time.Second):
+		t.Fatal("attempt was not expired by scheduler")
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"math"
+
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// questionPoints will return points of question, questions created before points were introduced are worth one point.
+func questionPoints(question *models.Question) float64 {
+	if question.Points == 0 {
+		return 1
+	}
+
+	return question.Points
+}
+
+// scoreAnswer will return score for an answer given credit between 0 and 1.
+// Answers without any credit are penalised by the negative marking ratio of quiz.
+func scoreAnswer(quiz *models.Quiz, question *models.Question, credit float64) float64 {
+	if credit > 0 {
+		return credit * questionPoints(question)
+	}
+
+	return -quiz.NegativeMarking * questionPoints(question)
+}
+
+// maxScore will return sum of points of all questions of quiz.
+func maxScore(quiz *models.Quiz) float64 {
+	total := 0.0
+
+	for i := range quiz.Questions {
+		total += questionPoints(&quiz.Questions[i])
+	}
+
+	return total
+}
+
+// updateAttemptScore will add score of response to the attempt and compute its max score and percentage.
+func updateAttemptScore(attempt *models.UserQuizAttempts, quiz *models.Quiz, score float64) {
+	attempt.TotalScore += score
+	attempt.MaxScore = maxScore(quiz)
+	attempt.Percentage = percentage(attempt.TotalScore, attempt.MaxScore)
+}
+
+// percentage will return score as percentage of max score rounded to 2 decimals.
+func percentage(score, maxScore float64) float64 {
+	if maxScore == 0 {
+		return 0
+	}
+
+	return math.Round(score/maxScore*10000) / 100
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// attemptQuestions will return questions of quiz without their answers, in the order they are shown in given attempt.
+// Questions and options are shuffled only if quiz enables it. Order is derived from attempt ID, so it is the
+// same every time questions of an attempt are fetched, and options of a question do not depend on order of questions.
+func attemptQuestions(attempt *models.UserQuizAttempts, quiz *models.Quiz) []models.Question {
+	questions := make([]models.Question, 0, len(quiz.Questions))
+
+	for _, question := range quiz.Questions {
+		question = copyQuestion(question)
+
+		if quiz.ShuffleOptions {
+			random := newAttemptRand(attempt.ID, question.ID)
+			random.Shuffle(len(question.Options), func(i, j int) {
+				question.Options[i], question.Options[j] = question.Options[j], question.Options[i]
+			})
+		}
+
+		questions = append(questions, question)
+	}
+
+	if quiz.ShuffleQuestions {
+		random := newAttemptRand(attempt.ID, quiz.ID)
+		random.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+		})
+	}
+
+	return questions
+}
+
+// newAttemptRand will create random number generator seeded by attempt ID along with ID of what is being shuffled.
+func newAttemptRand(attemptID, shuffledID uuid.UUID) *rand.Rand {
+	seed := binary.BigEndian.Uint64(attemptID[:8]) ^ binary.BigEndian.Uint64(attemptID[8:]) ^
+		binary.BigEndian.Uint64(shuffledID[:8]) ^ binary.BigEndian.Uint64(shuffledID[8:])
+
+	return rand.New(rand.NewSource(int64(seed)))
+}
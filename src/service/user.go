@@ -18,11 +18,11 @@ type UserService interface {
 
 // userService will contain reference to db.
 type userService struct {
-	db *db.Database
+	db db.Repository
 }
 
 // NewUserService will create new instance of userService
-func NewUserService(db *db.Database) UserService {
+func NewUserService(db db.Repository) UserService {
 	return &userService{
 		db: db,
 	}
@@ -47,7 +47,14 @@ func (service *userService) Register(user *models.User) (*models.LoginResponse,
 	user.Password = string(password)
 	user.ID = uuid.New()
 
-	service.db.Users = append(service.db.Users, *user)
+	err = service.db.CreateUser(user)
+	if errors.Is(err, db.ErrDuplicateRecord) {
+		return nil, ErrUsernameExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
 
 	loginResponse := models.LoginResponse{
 		ID:       user.ID,
@@ -75,7 +82,7 @@ func (service *userService) Login(login *models.Login) (*models.LoginResponse, e
 
 	err = security.ComparePassword(user.Password, login.Password)
 	if err != nil {
-		return nil, errors.New("username or password is incorrect")
+		return nil, ErrInvalidCredentials
 	}
 
 	loginResponse := models.LoginResponse{
@@ -94,22 +101,28 @@ func (service *userService) Login(login *models.Login) (*models.LoginResponse, e
 
 // checkDuplicateExist will check if same username already exists in database.
 func (service *userService) checkDuplicateExist(username string) error {
-	for _, user := range service.db.Users {
-		if strings.EqualFold(strings.ToLower(user.Username), strings.ToLower(username)) {
-			return errors.New("same username already exists")
-		}
+	_, err := service.db.GetUserByUsername(username)
+	if err == nil {
+		return ErrUsernameExists
 	}
 
-	return nil
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil
+	}
+
+	return err
 }
 
 // getUserByUsername will fetch user by username. If not found, it will return error
 func (service *userService) getUserByUsername(username string) (*models.User, error) {
-	for _, user := range service.db.Users {
-		if strings.EqualFold(strings.ToLower(user.Username), strings.ToLower(username)) {
-			return &user, nil
-		}
+	user, err := service.db.GetUserByUsername(username)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.New("username or password is incorrect")
+	return user, nil
 }
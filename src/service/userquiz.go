@@ -10,88 +10,218 @@ import (
 	"github.com/shaileshhb/quiz/src/db/validations"
 )
 
+// maxUpdateRetries is the number of times an attempt update is retried on version conflict.
+const maxUpdateRetries = 100
+
 // UserQuizService will consist of service methods that would be implemented by userQuizService
 type UserQuizService interface {
 	StartQuiz(*models.UserQuizAttempts) error
-	SubmitAnswer(*models.UserResponse) (*models.Option, error)
-	GetUserQuizResults(uuid.UUID, uuid.UUID) (*models.UserQuizAttempts, error)
+	SubmitAnswer(*models.UserResponse) (*models.AnswerResult, error)
+	GetUserQuizResults(uuid.UUID, uuid.UUID) (*models.UserQuizResult, error)
+	GetAttemptResults(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error)
+	ListAttempts(userID, quizID uuid.UUID) (*models.UserQuizAttemptList, error)
+	GetCurrentAttempt(userID, quizID uuid.UUID) (*models.CurrentAttempt, error)
+	GetAttemptQuestions(userID, quizID, attemptID uuid.UUID) (*models.Quiz, error)
+	FinishAttempt(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error)
+	ExpireAttempts() (int, error)
+}
+
+// AttemptEndedHook will be called with the attempt once it has ended and has been stored.
+type AttemptEndedHook func(attempt models.UserQuizAttempts)
+
+// UserQuizOption will configure optional dependencies of userQuizService.
+type UserQuizOption func(service *userQuizService)
+
+// WithClock will make userQuizService read current time from given clock instead of system clock.
+func WithClock(clock Clock) UserQuizOption {
+	return func(service *userQuizService) {
+		service.clock = clock
+	}
+}
+
+// WithAttemptEndedHook will add hook which is called whenever an attempt ends,
+// either because all questions were answered or because maximum time of quiz exceeded.
+func WithAttemptEndedHook(hook AttemptEndedHook) UserQuizOption {
+	return func(service *userQuizService) {
+		service.attemptEndedHooks = append(service.attemptEndedHooks, hook)
+	}
 }
 
 // userQuizService will contain reference to db.
 type userQuizService struct {
-	db *db.Database
+	db                db.Repository
+	clock             Clock
+	attemptEndedHooks []AttemptEndedHook
 }
 
 // NewUserQuizService will create new instance of userQuizService
-func NewUserQuizService(db *db.Database) UserQuizService {
-	return &userQuizService{
-		db: db,
+func NewUserQuizService(db db.Repository, options ...UserQuizOption) UserQuizService {
+	service := &userQuizService{
+		db:    db,
+		clock: systemClock{},
+	}
+
+	for _, option := range options {
+		option(service)
 	}
+
+	return service
 }
 
-// StartQuiz will start a quiz for a user.
+// StartQuiz will start a new attempt of quiz for a user. A user can attempt the quiz as many times as its
+// max attempts, one attempt at a time and only after cooldown of quiz has passed since previous attempt ended.
 func (service *userQuizService) StartQuiz(userQuiz *models.UserQuizAttempts) error {
 
-	err := validations.DoesUserIDExist(service.db, userQuiz.UserID)
+	err := service.checkUserExists(userQuiz.UserID)
+	if err != nil {
+		return err
+	}
+
+	quiz, err := service.getQuizByID(userQuiz.QuizID)
 	if err != nil {
 		return err
 	}
 
-	err = validations.DoesQuizIDExist(service.db, userQuiz.QuizID)
+	if quiz.IsArchived {
+		return ErrQuizArchived
+	}
+
+	if isQuizClosed(quiz, service.clock.Now()) {
+		return ErrQuizClosed
+	}
+
+	attempts, err := service.db.ListAttemptsByUserAndQuiz(userQuiz.UserID, userQuiz.QuizID)
 	if err != nil {
 		return err
 	}
 
-	// check if user has attempted this quiz
-	for _, attempt := range service.db.UserQuizAttempts {
-		if attempt.UserID == userQuiz.UserID && attempt.QuizID == userQuiz.QuizID {
-			return errors.New("user has already attempted this quiz")
+	if uint32(len(attempts)) >= attemptsAllowed(quiz) {
+		return ErrAttemptsExhausted
+	}
+
+	startTime := service.clock.Now()
+	userQuiz.AttemptNumber = 1
+
+	if len(attempts) > 0 {
+		previous := &attempts[len(attempts)-1]
+
+		// previous attempt could have expired without being ended by the scheduler yet.
+		if previous.EndedAt == nil {
+			_, err = service.expireAttemptByID(previous, startTime)
+			if err != nil {
+				return err
+			}
 		}
+
+		if previous.EndedAt == nil {
+			return ErrAttemptInProgress
+		}
+
+		if next := nextAttemptAt(previous, quiz); next != nil && startTime.Before(*next) {
+			return ErrAttemptCooldown.withMessage("next attempt of this quiz can be started after " + next.Format(time.RFC3339))
+		}
+
+		userQuiz.AttemptNumber = previous.Number() + 1
 	}
 
-	startTime := time.Now()
+	expiresAt := startTime.Add(maxDuration(quiz))
 	userQuiz.StartedAt = &startTime
+	userQuiz.ExpiresAt = &expiresAt
+	userQuiz.EndedAt = nil
+	userQuiz.QuestionIDs = drawQuestions(quiz)
+	restrictToAttempt(quiz, userQuiz)
+
 	userQuiz.TotalScore = 0
+	userQuiz.MaxScore = maxScore(quiz)
+	userQuiz.Percentage = 0
+	userQuiz.Outcome = ""
+	userQuiz.UserResponses = nil
 	userQuiz.ID = uuid.New()
+	userQuiz.Version = 0
+	userQuiz.QuizVersion = quiz.Version
+
+	// unique constraint of the database protects against concurrent starts,
+	// the attempt started by the concurrent request is still in progress.
+	err = service.db.CreateAttempt(userQuiz)
+	if errors.Is(err, db.ErrDuplicateRecord) {
+		return ErrAttemptInProgress
+	}
 
-	service.db.UserQuizAttempts = append(service.db.UserQuizAttempts, *userQuiz)
+	// quiz was deleted after it was read.
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrQuizNotFound
+	}
 
-	return nil
+	return err
 }
 
 // SubmitAnswer will submit user's answer for a given question and return correct answer and error if any.
-func (service *userQuizService) SubmitAnswer(userResponse *models.UserResponse) (*models.Option, error) {
-	err := validations.DoesUserIDExist(service.db, userResponse.UserID)
+func (service *userQuizService) SubmitAnswer(userResponse *models.UserResponse) (*models.AnswerResult, error) {
+	err := service.checkUserExists(userResponse.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validations.DoesQuizIDExist(service.db, userResponse.QuizID)
+	err = service.checkQuizExists(userResponse.QuizID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.isQuizCompleted(userResponse)
+	// attempt is updated using compare-and-swap, so submission is retried if it was modified concurrently.
+	for i := 0; i < maxUpdateRetries; i++ {
+		answerResult, err := service.submitAnswer(userResponse)
+		if !errors.Is(err, db.ErrVersionConflict) {
+			return answerResult, err
+		}
+	}
+
+	return nil, ErrAttemptBusy
+}
+
+// submitAnswer will grade user's answer against the latest attempt and store it.
+// Answer is graded against the version of quiz which the attempt was started on, using the type of question.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) submitAnswer(userResponse *models.UserResponse) (*models.AnswerResult, error) {
+	userQuiz, err := service.getUserQuiz(userResponse.UserID, userResponse.QuizID, userResponse.UserQuizAttemptID)
 	if err != nil {
 		return nil, err
 	}
 
-	userQuiz, err := service.getUserQuiz(userResponse.UserQuizAttemptID)
+	quiz, err := service.getAttemptQuiz(userQuiz)
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.isQuestionAnswered(userQuiz, userResponse.QuestionID)
+	// feedback mode and closing time are taken from the current quiz, so that changing them affects every attempt.
+	currentQuiz, err := service.getQuizByID(userResponse.QuizID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.doesQuestionExistForQuiz(userResponse.QuizID, userResponse.QuestionID)
+	if isAttemptExpired(userQuiz, quiz, service.clock.Now()) {
+		err = service.expireAttempt(userQuiz, quiz)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ErrTimeExceeded
+	}
+
+	if isQuizClosed(currentQuiz, service.clock.Now()) {
+		return nil, ErrQuizClosed.withMessage("quiz has closed and cannot be answered")
+	}
+
+	err = service.isQuizCompleted(userQuiz)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.isQuestionAnswered(userQuiz, userResponse.QuestionID)
 	if err != nil {
 		return nil, err
 	}
 
-	quiz, err := service.getQuizByID(userResponse.QuizID)
+	err = service.doesQuestionExistForQuiz(quiz, userResponse.QuestionID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,172 +231,587 @@ func (service *userQuizService) SubmitAnswer(userResponse *models.UserResponse)
 		return nil, err
 	}
 
-	correctOption := &models.Option{}
-	option, err := service.getOptionByID(question, userResponse.SelectedOptionID)
+	questionType, err := models.GetQuestionType(question.Type)
 	if err != nil {
 		return nil, err
 	}
 
-	if *option.IsCorrect {
-		userResponse.IsCorrect = true
-		service.updateUserQuizScore(userResponse.UserQuizAttemptID)
-		correctOption = option
-	} else {
-		// get correct option
-		correctOption, err = service.getCorrectOption(question)
+	credit, err := questionType.Grade(question, userResponse)
+	if err != nil {
+		return nil, InvalidRequest(err)
+	}
+
+	userResponse.BankQuestionID = question.BankQuestionID
+	isCorrect := credit == models.FullCredit
+	score := scoreAnswer(quiz, question, credit)
+	userResponse.IsCorrect = &isCorrect
+	userResponse.Score = &score
+	updateAttemptScore(userQuiz, quiz, score)
+
+	userResponse.ID = uuid.New()
+	userQuiz.UserResponses = append(userQuiz.UserResponses, *userResponse)
+
+	// quiz only has questions drawn for the attempt, so attempt ends once all of them are answered.
+	if len(quiz.Questions) == len(userQuiz.UserResponses) {
+		finishAttempt(userQuiz, quiz, service.clock.Now())
+	}
+
+	err = service.db.UpdateAttempt(userQuiz)
+	if err != nil {
+		return nil, err
+	}
+
+	if userQuiz.EndedAt != nil {
+		service.attemptEnded(userQuiz)
+	}
+
+	answerResult := &models.AnswerResult{
+		FeedbackMode: feedbackMode(currentQuiz),
+		MaxScore:     userQuiz.MaxScore,
+	}
+
+	if userQuiz.EndedAt != nil || isFeedbackRevealed(currentQuiz, userQuiz, service.clock.Now()) {
+		answerResult.TotalScore = &userQuiz.TotalScore
+		answerResult.Percentage = &userQuiz.Percentage
+	}
+
+	if !isFeedbackRevealed(currentQuiz, userQuiz, service.clock.Now()) {
+		return answerResult, nil
+	}
+
+	answerResult.IsCorrect = userResponse.IsCorrect
+	answerResult.Score = userResponse.Score
+	answerResult.CorrectAnswer = questionType.CorrectAnswer(question)
+
+	answerResult.Explanation = question.Explanation
+	answerResult.SelectedOptions = selectedOptions(question, userResponse)
+
+	if len(answerResult.CorrectAnswer.Options) > 0 {
+		answerResult.CorrectOption = &answerResult.CorrectAnswer.Options[0]
+	}
+
+	return answerResult, nil
+}
+
+// GetUserQuizResults will return results of the latest attempt of specific quiz for specified user.
+// Results contain the version of quiz which the attempt was made on. If maximum time of quiz
+// has exceeded, attempt is ended before its results are returned.
+func (service *userQuizService) GetUserQuizResults(userID, quizID uuid.UUID) (*models.UserQuizResult, error) {
+
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.checkQuizExists(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		result, err := service.getUserQuizResults(userID, quizID)
+		if !errors.Is(err, db.ErrVersionConflict) {
+			return result, err
+		}
+	}
+
+	return nil, ErrAttemptBusy
+}
+
+// getUserQuizResults will fetch latest attempt of user and return its results.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) getUserQuizResults(userID, quizID uuid.UUID) (*models.UserQuizResult, error) {
+	attempt, err := service.db.GetAttemptByUserAndQuiz(userID, quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizNotAttempted
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return service.getAttemptResult(attempt)
+}
+
+// GetAttemptResults will return results of given attempt of specific quiz made by specified user.
+// If maximum time of quiz has exceeded, attempt is ended before its results are returned.
+func (service *userQuizService) GetAttemptResults(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error) {
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.checkQuizExists(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		result, err := service.getAttemptResults(userID, quizID, attemptID)
+		if !errors.Is(err, db.ErrVersionConflict) {
+			return result, err
+		}
+	}
+
+	return nil, ErrAttemptBusy
+}
+
+// getAttemptResults will fetch given attempt of user and return its results.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) getAttemptResults(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error) {
+	attempt, err := service.getOwnedAttempt(userID, quizID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.getAttemptResult(attempt)
+}
+
+// GetAttemptQuestions will return quiz along with its questions in the order they are shown in given attempt
+// of specified user, without their answers. Questions are fetched from the version of quiz the attempt was started on.
+func (service *userQuizService) GetAttemptQuestions(userID, quizID, attemptID uuid.UUID) (*models.Quiz, error) {
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := service.getOwnedAttempt(userID, quizID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := service.getAttemptQuiz(attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptQuiz := copyQuiz(*quiz)
+	attemptQuiz.Questions = attemptQuestions(attempt, quiz)
+
+	return &attemptQuiz, nil
+}
+
+// getOwnedAttempt will fetch attempt by given attemptID, only if it was made by given user for given quiz.
+func (service *userQuizService) getOwnedAttempt(userID, quizID, attemptID uuid.UUID) (*models.UserQuizAttempts, error) {
+	attempt, err := service.db.GetAttemptByID(attemptID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrAttemptNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.UserID != userID || attempt.QuizID != quizID {
+		return nil, ErrAttemptNotFound
+	}
+
+	return attempt, nil
+}
+
+// getAttemptResult will end attempt if it has expired and return it along with the version of quiz it was made on.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) getAttemptResult(attempt *models.UserQuizAttempts) (*models.UserQuizResult, error) {
+	quiz, err := service.getAttemptQuiz(attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAttemptExpired(attempt, quiz, service.clock.Now()) {
+		err = service.expireAttempt(attempt, quiz)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	userResponse.ID = uuid.New()
+	return service.newAttemptResult(attempt, quiz)
+}
+
+// newAttemptResult will return attempt along with the version of quiz it was made on. Correctness of its responses
+// is hidden unless feedback mode of the current quiz reveals it, in which case explanations of questions are added.
+func (service *userQuizService) newAttemptResult(attempt *models.UserQuizAttempts, quiz *models.Quiz) (*models.UserQuizResult, error) {
+	currentQuiz, err := service.getQuizByID(attempt.QuizID)
+	if err != nil {
+		return nil, err
+	}
 
-	for i, attempts := range service.db.UserQuizAttempts {
-		if attempts.ID == userResponse.UserQuizAttemptID {
-			service.db.UserQuizAttempts[i].UserResponses = append(service.db.UserQuizAttempts[i].UserResponses, *userResponse)
+	result := &models.UserQuizResult{
+		UserQuizAttempts: *attempt,
+		Quiz:             copyQuiz(*quiz),
+	}
 
-			if len(quiz.Questions) == len(service.db.UserQuizAttempts[i].UserResponses) {
-				endedAt := time.Now()
-				service.db.UserQuizAttempts[i].EndedAt = &endedAt
-				break
-			}
-			break
-		}
+	if isFeedbackRevealed(currentQuiz, attempt, service.clock.Now()) {
+		addExplanations(&result.Quiz, quiz)
 	}
 
-	return correctOption, nil
+	hideFeedback(&result.UserQuizAttempts, currentQuiz, service.clock.Now())
+	return result, nil
 }
 
-// GetUserQuizResults will return results for specific quiz for specified user.
-func (service *userQuizService) GetUserQuizResults(userID, quizID uuid.UUID) (*models.UserQuizAttempts, error) {
+// ListAttempts will return every attempt of specific quiz made by specified user along with their final result
+// according to scoring policy of quiz. Attempts whose maximum time has exceeded are ended before they are returned.
+func (service *userQuizService) ListAttempts(userID, quizID uuid.UUID) (*models.UserQuizAttemptList, error) {
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
 
-	err := validations.DoesUserIDExist(service.db, userID)
+	quiz, err := service.getQuizByID(quizID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validations.DoesQuizIDExist(service.db, quizID)
+	attempts, err := service.db.ListAttemptsByUserAndQuiz(userID, quizID)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, attempt := range service.db.UserQuizAttempts {
-		if attempt.UserID == userID && attempt.QuizID == quizID {
-			return &attempt, nil
+	now := service.clock.Now()
+
+	for i := range attempts {
+		if attempts[i].EndedAt != nil {
+			continue
+		}
+
+		_, err = service.expireAttemptByID(&attempts[i], now)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, errors.New("user not attempted specified quiz")
+	attemptList := summarizeAttempts(attempts, quiz, now)
+	for i := range attemptList.Attempts {
+		hideFeedback(&attemptList.Attempts[i], quiz, now)
+	}
+
+	return attemptList, nil
 }
 
-// updateUserQuizScore will updaate the total score of the UserQuizAttempts.
-func (service *userQuizService) updateUserQuizScore(userQuizAttemptID uuid.UUID) {
-	for i, attempts := range service.db.UserQuizAttempts {
-		if attempts.ID == userQuizAttemptID {
-			service.db.UserQuizAttempts[i].TotalScore++
+// GetCurrentAttempt will return attempt of specific quiz which specified user has started but not ended yet,
+// so that it can be continued on another device. Attempt is ended instead if maximum time of quiz has exceeded.
+func (service *userQuizService) GetCurrentAttempt(userID, quizID uuid.UUID) (*models.CurrentAttempt, error) {
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.checkQuizExists(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := service.db.GetAttemptByUserAndQuiz(userID, quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrNoAttemptInProgress
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := service.clock.Now()
+
+	if attempt.EndedAt == nil {
+		_, err = service.expireAttemptByID(attempt, now)
+		if err != nil {
+			return nil, err
 		}
 	}
+
+	if attempt.EndedAt != nil {
+		return nil, ErrNoAttemptInProgress
+	}
+
+	quiz, err := service.getAttemptQuiz(attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	currentQuiz, err := service.getQuizByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentAttempt := &models.CurrentAttempt{
+		UserQuizAttempts:    *attempt,
+		RemainingSeconds:    int64(attempt.StartedAt.Add(maxDuration(quiz)).Sub(now) / time.Second),
+		TotalQuestions:      len(quiz.Questions),
+		AnsweredQuestionIDs: []uuid.UUID{},
+	}
+
+	answered := map[uuid.UUID]bool{}
+	for _, response := range attempt.UserResponses {
+		answered[response.QuestionID] = true
+		currentAttempt.AnsweredQuestionIDs = append(currentAttempt.AnsweredQuestionIDs, response.QuestionID)
+	}
+
+	for _, question := range attemptQuestions(attempt, quiz) {
+		if !answered[question.ID] {
+			nextQuestion := question
+			currentAttempt.NextQuestion = &nextQuestion
+			break
+		}
+	}
+
+	hideFeedback(&currentAttempt.UserQuizAttempts, currentQuiz, now)
+
+	return currentAttempt, nil
 }
 
-// getQuizByID will fetch quiz by given quizID.
-func (service *userQuizService) getQuizByID(quizID uuid.UUID) (*models.Quiz, error) {
-	for _, quiz := range service.db.Quiz {
-		if quiz.ID == quizID {
-			return &quiz, nil
+// FinishAttempt will end attempt before all questions are answered and return its final result.
+// Unanswered questions are recorded as skipped and do not score any points. If maximum time of quiz
+// has already exceeded, attempt is ended as timed out instead.
+func (service *userQuizService) FinishAttempt(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error) {
+	err := service.checkUserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.checkQuizExists(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		result, err := service.finishAttempt(userID, quizID, attemptID)
+		if !errors.Is(err, db.ErrVersionConflict) {
+			return result, err
 		}
 	}
 
-	return nil, errors.New("quiz not found")
+	return nil, ErrAttemptBusy
 }
 
-// getQuestionByID will fetch question by given questionID.
-func (service *userQuizService) getQuestionByID(quiz *models.Quiz, questionID uuid.UUID) (*models.Question, error) {
-	for _, question := range quiz.Questions {
-		if question.ID == questionID {
-			return &question, nil
+// finishAttempt will skip unanswered questions of the latest attempt and end it.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) finishAttempt(userID, quizID, attemptID uuid.UUID) (*models.UserQuizResult, error) {
+	userQuiz, err := service.getUserQuiz(userID, quizID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := service.getAttemptQuiz(userQuiz)
+	if err != nil {
+		return nil, err
+	}
+
+	if userQuiz.EndedAt != nil {
+		return nil, ErrAttemptEnded
+	}
+
+	if isAttemptExpired(userQuiz, quiz, service.clock.Now()) {
+		err = service.expireAttempt(userQuiz, quiz)
+	} else {
+		skipUnansweredQuestions(userQuiz, quiz)
+		finishAttempt(userQuiz, quiz, service.clock.Now())
+
+		err = service.db.UpdateAttempt(userQuiz)
+		if err == nil {
+			service.attemptEnded(userQuiz)
 		}
 	}
 
-	return nil, errors.New("question not found")
+	if err != nil {
+		return nil, err
+	}
+
+	return service.newAttemptResult(userQuiz, quiz)
 }
 
-// getOptionByID will fetch option by given optionID.
-func (service *userQuizService) getOptionByID(question *models.Question, optionID uuid.UUID) (*models.Option, error) {
-	for _, option := range question.Options {
-		if option.ID == optionID {
-			return &option, nil
+// ExpireAttempts will end all attempts whose maximum time has exceeded and return number of attempts ended.
+// Attempts which fail to expire are skipped and their errors are returned along with the number of attempts ended.
+func (service *userQuizService) ExpireAttempts() (int, error) {
+	now := service.clock.Now()
+
+	attempts, err := service.db.ListExpiredAttempts(now)
+	if err != nil {
+		return 0, err
+	}
+
+	totalExpired := 0
+	var errs []error
+
+	for i := range attempts {
+		isExpired, err := service.expireAttemptByID(&attempts[i], now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if isExpired {
+			totalExpired++
 		}
 	}
 
-	return nil, errors.New("option not found")
+	return totalExpired, errors.Join(errs...)
 }
 
-// getCorrectOption will fetch correct option.
-func (service *userQuizService) getCorrectOption(question *models.Question) (*models.Option, error) {
-	for _, option := range question.Options {
-		if *option.IsCorrect {
-			return &option, nil
+// expireAttemptByID will end attempt if it has expired, retrying with the latest attempt if it was modified concurrently.
+// It returns whether attempt was ended by this call, attempt is updated to the stored attempt in either case.
+func (service *userQuizService) expireAttemptByID(attempt *models.UserQuizAttempts, now time.Time) (bool, error) {
+	for i := 0; i < maxUpdateRetries; i++ {
+		quiz, err := service.getAttemptQuiz(attempt)
+		if err != nil {
+			return false, err
+		}
+
+		if !isAttemptExpired(attempt, quiz, now) {
+			return false, nil
 		}
+
+		err = service.expireAttempt(attempt, quiz)
+		if !errors.Is(err, db.ErrVersionConflict) {
+			return err == nil, err
+		}
+
+		latest, err := service.db.GetAttemptByID(attempt.ID)
+		if err != nil {
+			return false, err
+		}
+
+		*attempt = *latest
 	}
 
-	return nil, errors.New("correct option not found")
+	return false, ErrAttemptBusy
 }
 
-// doesQuestionExistForQuiz will check if question exist in the given quiz.
-func (service *userQuizService) doesQuestionExistForQuiz(quizID, questionID uuid.UUID) error {
+// expireAttempt will end attempt whose maximum time has exceeded and store it.
+// It returns db.ErrVersionConflict if attempt was modified after it was read.
+func (service *userQuizService) expireAttempt(attempt *models.UserQuizAttempts, quiz *models.Quiz) error {
+	expireAttempt(attempt, quiz)
 
-	quiz, err := service.getQuizByID(quizID)
+	err := service.db.UpdateAttempt(attempt)
 	if err != nil {
 		return err
 	}
 
+	service.attemptEnded(attempt)
+	return nil
+}
+
+// attemptEnded will call all hooks registered for ended attempts.
+func (service *userQuizService) attemptEnded(attempt *models.UserQuizAttempts) {
+	for _, hook := range service.attemptEndedHooks {
+		hook(*attempt)
+	}
+}
+
+// getQuizByID will fetch quiz by given quizID.
+func (service *userQuizService) getQuizByID(quizID uuid.UUID) (*models.Quiz, error) {
+	quiz, err := service.db.GetQuizByID(quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// checkUserExists will check that user with given userID exists.
+func (service *userQuizService) checkUserExists(userID uuid.UUID) error {
+	err := validations.DoesUserIDExist(service.db, userID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+
+	return err
+}
+
+// checkQuizExists will check that quiz with given quizID exists.
+func (service *userQuizService) checkQuizExists(quizID uuid.UUID) error {
+	err := validations.DoesQuizIDExist(service.db, quizID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrQuizNotFound
+	}
+
+	return err
+}
+
+// getAttemptQuiz will fetch version of quiz which the attempt was started on, containing only questions
+// drawn for the attempt. Every check done for an attempt uses these questions instead of all questions of quiz.
+func (service *userQuizService) getAttemptQuiz(attempt *models.UserQuizAttempts) (*models.Quiz, error) {
+	var quiz *models.Quiz
+	var err error
+
+	// attempts started before versioning was introduced are graded against the current quiz.
+	if attempt.QuizVersion == 0 {
+		quiz, err = service.getQuizByID(attempt.QuizID)
+	} else {
+		quiz, err = service.db.GetQuizVersion(attempt.QuizID, attempt.QuizVersion)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, ErrQuizVersionNotFound
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	restrictToAttempt(quiz, attempt)
+	return quiz, nil
+}
+
+// getQuestionByID will fetch question by given questionID.
+func (service *userQuizService) getQuestionByID(quiz *models.Quiz, questionID uuid.UUID) (*models.Question, error) {
+	for _, question := range quiz.Questions {
+		if question.ID == questionID {
+			return &question, nil
+		}
+	}
+
+	return nil, ErrQuestionNotFound
+}
+
+// doesQuestionExistForQuiz will check if question exist in the given quiz, which only has questions drawn for the attempt.
+func (service *userQuizService) doesQuestionExistForQuiz(quiz *models.Quiz, questionID uuid.UUID) error {
 	for _, question := range quiz.Questions {
 		if question.ID == questionID {
 			return nil
 		}
 	}
-	return errors.New("question not found for specified quiz")
+	return ErrQuestionNotFound.withMessage("question not found for specified quiz")
 }
 
 // getUserQuiz will check if quiz has started for a given user, if not then it will return an error
-func (service *userQuizService) getUserQuiz(userQuizAttemptID uuid.UUID) (*models.UserQuizAttempts, error) {
-	for _, attempts := range service.db.UserQuizAttempts {
-		if attempts.ID == userQuizAttemptID {
-			return &attempts, nil
-		}
+func (service *userQuizService) getUserQuiz(userID, quizID, attemptID uuid.UUID) (*models.UserQuizAttempts, error) {
+	attempt, err := service.db.GetAttemptByID(attemptID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil, ErrAttemptNotStarted
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.UserID != userID || attempt.QuizID != quizID {
+		return nil, ErrAttemptNotStarted
 	}
-	return nil, errors.New("please start quiz before submitting answers")
+
+	return attempt, nil
 }
 
 // isQuestionAnswered will check if all question has been answered for a given user, if yes then it will return an error
 func (service *userQuizService) isQuestionAnswered(userQuiz *models.UserQuizAttempts, questionID uuid.UUID) error {
 	for _, repsonse := range userQuiz.UserResponses {
 		if repsonse.QuestionID == questionID {
-			return errors.New("question already answered")
+			return ErrQuestionAnswered
 		}
 	}
 
 	return nil
 }
 
-// isQuizCompleted will check if quiz has ended or max time is exceeded.
-func (service *userQuizService) isQuizCompleted(userResponse *models.UserResponse) error {
-	userQuiz, err := service.getUserQuiz(userResponse.UserQuizAttemptID)
-	if err != nil {
-		return err
+// isQuizCompleted will check if attempt has ended.
+func (service *userQuizService) isQuizCompleted(userQuiz *models.UserQuizAttempts) error {
+	if userQuiz.Outcome == models.OutcomeTimedOut || userQuiz.Outcome == models.OutcomeAbandoned {
+		return ErrTimeExceeded
 	}
 
 	if userQuiz.EndedAt != nil {
-		return errors.New("cannot answer questions after quiz has ended")
-	}
-
-	quiz, err := service.getQuizByID(userQuiz.QuizID)
-	if err != nil {
-		return err
-	}
-
-	if time.Since(*userQuiz.StartedAt) > time.Duration(quiz.MaxTime*uint64(time.Minute)) {
-		return errors.New("maximum time exceeded for this quiz")
+		return ErrAttemptEnded.withMessage("cannot answer questions after quiz has ended")
 	}
 
 	return nil
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shaileshhb/quiz/src/db"
+	"github.com/shaileshhb/quiz/src/db/models"
+)
+
+// BenchmarkSubmitAnswer will measure submit latency as number of quizzes and attempts grows.
+// Latency should stay flat across sizes as every lookup is served by an index.
+func BenchmarkSubmitAnswer(b *testing.B) {
+	sizes := []struct {
+		quizzes  int
+		attempts int
+	}{
+		{quizzes: 100, attempts: 1000},
+		{quizzes: 1000, attempts: 10000},
+		{quizzes: 10000, attempts: 100000},
+	}
+
+	for _, testDatabase := range testDatabases {
+		for _, size := range sizes {
+			name := fmt.Sprintf("%s/quizzes=%d/attempts=%d", testDatabase.name, size.quizzes, size.attempts)
+			open := testDatabase.open
+			quizzes, attempts := size.quizzes, size.attempts
+
+			b.Run(name, func(b *testing.B) {
+				database, err := open()
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer database.Close()
+
+				fillDatabase(b, database, quizzes, attempts)
+				quiz := createTestQuiz(b, database, 10)
+				serv := NewUserQuizService(database)
+
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					response := startTestAttempt(b, database, quiz)
+					b.StartTimer()
+
+					_, err := serv.SubmitAnswer(response)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// fillDatabase will add given number of quizzes and attempts spread across those quizzes.
+func fillDatabase(b *testing.B, database db.Repository, totalQuizzes, totalAttempts int) {
+	trueValue := true
+	quizIDs := make([]uuid.UUID, totalQuizzes)
+
+	for i := range quizIDs {
+		quiz := models.Quiz{
+			ID:      uuid.New(),
+			Title:   fmt.Sprintf("Benchmark quiz %d", i),
+			MaxTime: 2,
+			Questions: []models.Question{
+				{
+					ID:      uuid.New(),
+					Text:    "Question",
+					Options: []models.Option{{ID: uuid.New(), Answer: "Answer", IsCorrect: &trueValue}},
+				},
+			},
+		}
+
+		err := database.CreateQuiz(&quiz)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		quizIDs[i] = quiz.ID
+	}
+
+	startedAt := time.Now()
+
+	for i := 0; i < totalAttempts; i++ {
+		attempt := models.UserQuizAttempts{
+			ID:        uuid.New(),
+			UserID:    uuid.New(),
+			QuizID:    quizIDs[i%totalQuizzes],
+			StartedAt: &startedAt,
+		}
+
+		err := database.CreateAttempt(&attempt)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// startTestAttempt will register a new user, start given quiz for the user and return response for first question.
+func startTestAttempt(b *testing.B, database db.Repository, quiz *models.Quiz) *models.UserResponse {
+	user := models.User{
+		ID:       uuid.New(),
+		Username: uuid.NewString(),
+	}
+
+	err := database.CreateUser(&user)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	userQuiz := models.UserQuizAttempts{
+		UserID: user.ID,
+		QuizID: quiz.ID,
+	}
+
+	err = NewUserQuizService(database).StartQuiz(&userQuiz)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return &models.UserResponse{
+		UserID:            user.ID,
+		QuizID:            quiz.ID,
+		UserQuizAttemptID: userQuiz.ID,
+		QuestionID:        quiz.Questions[0].ID,
+		SelectedOptionID:  quiz.Questions[0].Options[0].ID,
+	}
+}
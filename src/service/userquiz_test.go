@@ -1,6 +1,8 @@
 package service
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,137 +14,1004 @@ import (
 
 // TestStartQuizForInvalidUser will test start quiz for a user who does not exist.
 func TestStartQuizForInvalidUser(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
 
-	userQuiz := models.UserQuizAttempts{
-		UserID: uuid.New(),
-		QuizID: uuid.New(),
-	}
+		userQuiz := models.UserQuizAttempts{
+			UserID: uuid.New(),
+			QuizID: uuid.New(),
+		}
 
-	err := serv.StartQuiz(&userQuiz)
+		err := serv.StartQuiz(&userQuiz)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "user not found", err.Error())
+		assert.NotNil(t, err)
+		assert.Equal(t, "user not found", err.Error())
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
 }
 
 // TestStartQuizForInvalidQuiz will test start quiz for a quiz which does not exist.
 func TestStartQuizForInvalidQuiz(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
 
-	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
 
-	userQuiz := models.UserQuizAttempts{
-		UserID: userID,
-		QuizID: uuid.New(),
-	}
+		userQuiz := models.UserQuizAttempts{
+			UserID: userID,
+			QuizID: uuid.New(),
+		}
 
-	err := serv.StartQuiz(&userQuiz)
+		err := serv.StartQuiz(&userQuiz)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "quiz not found", err.Error())
+		assert.NotNil(t, err)
+		assert.Equal(t, "quiz not found", err.Error())
+	})
 }
 
 // TestStartQuizForAttemptedQuiz will test start quiz for a quiz for which user has already attempted.
 func TestStartQuizForAttemptedQuiz(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
 
-	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
-	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
 
-	userQuiz := models.UserQuizAttempts{
-		UserID: userID,
-		QuizID: quizID,
-	}
+		userQuiz := models.UserQuizAttempts{
+			UserID: userID,
+			QuizID: quizID,
+		}
 
-	_ = serv.StartQuiz(&userQuiz)
-	err := serv.StartQuiz(&userQuiz)
+		_ = serv.StartQuiz(&userQuiz)
+		err := serv.StartQuiz(&userQuiz)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "user has already attempted this quiz", err.Error())
+		assert.NotNil(t, err)
+		assert.Equal(t, "user has already attempted this quiz", err.Error())
+		assert.ErrorIs(t, err, ErrAttemptsExhausted)
+	})
 }
 
 // TestStartQuiz will test start quiz.
 func TestStartQuiz(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
 
-	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
-	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
 
-	userQuiz := models.UserQuizAttempts{
-		UserID: userID,
-		QuizID: quizID,
-	}
+		userQuiz := models.UserQuizAttempts{
+			UserID: userID,
+			QuizID: quizID,
+		}
 
-	totalQuizzes := len(database.UserQuizAttempts)
-	err := serv.StartQuiz(&userQuiz)
+		err := serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
 
-	assert.Nil(t, err)
-	assert.Equal(t, len(database.UserQuizAttempts), totalQuizzes+1)
+		attempt, err := database.GetAttemptByUserAndQuiz(userID, quizID)
+		assert.Nil(t, err)
+		assert.Equal(t, userQuiz.ID, attempt.ID)
+	})
 }
 
 // TestCompletedQuizSubmission will test for submission into completed quiz
 func TestCompletedQuizSubmission(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
-
-	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
-	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
-	startedTime := time.Now()
-	endedTime := time.Now().Add(2 * time.Minute)
-
-	userQuiz := models.UserQuizAttempts{
-		UserID:    userID,
-		QuizID:    quizID,
-		StartedAt: &startedTime,
-		EndedAt:   &endedTime,
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		startedTime := time.Now()
+		endedTime := time.Now().Add(2 * time.Minute)
+
+		userQuiz := models.UserQuizAttempts{
+			UserID:    userID,
+			QuizID:    quizID,
+			StartedAt: &startedTime,
+			EndedAt:   &endedTime,
+		}
+
+		response := models.UserResponse{
+			QuestionID:       uuid.New(),
+			SelectedOptionID: uuid.New(),
+			QuizID:           quizID,
+			UserID:           userID,
+		}
+
+		_ = database.CreateAttempt(&userQuiz)
+
+		_, err := serv.SubmitAnswer(&response)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "cannot answer questions after quiz has ended", err.Error())
+	})
+}
+
+// TestTimeExceededSubmission will test for submission after maximum time has passed
+func TestTimeExceededSubmission(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+		startedTime := time.Now().Add(-2 * time.Minute)
+
+		userQuiz := models.UserQuizAttempts{
+			UserID:    userID,
+			QuizID:    quizID,
+			StartedAt: &startedTime,
+			EndedAt:   nil,
+		}
+
+		response := models.UserResponse{
+			QuestionID:       uuid.New(),
+			SelectedOptionID: uuid.New(),
+			QuizID:           quizID,
+			UserID:           userID,
+		}
+
+		_ = database.CreateAttempt(&userQuiz)
+
+		_, err := serv.SubmitAnswer(&response)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "maximum time exceeded for this quiz", err.Error())
+		assert.ErrorIs(t, err, ErrGone)
+
+		result, err := serv.GetUserQuizResults(userID, quizID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.OutcomeAbandoned, result.Outcome)
+		assert.Equal(t, startedTime.Add(time.Minute).Unix(), result.EndedAt.Unix())
+	})
+}
+
+// TestConcurrentStartQuiz will test that only one attempt is created when same quiz is started concurrently.
+func TestConcurrentStartQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+
+		quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		var wg sync.WaitGroup
+		var started int32
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				userQuiz := models.UserQuizAttempts{
+					UserID: userID,
+					QuizID: quizID,
+				}
+
+				err := serv.StartQuiz(&userQuiz)
+				if err == nil {
+					atomic.AddInt32(&started, 1)
+					return
+				}
+
+				assert.Contains(t, []error{ErrAttemptsExhausted, ErrAttemptInProgress}, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), started)
+	})
+}
+
+// TestConcurrentRetake will test that a start which loses the race against a concurrent start of the same retake
+// is told that an attempt is in progress, instead of that the quiz was already attempted.
+func TestConcurrentRetake(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		maxAttempts := uint32(3)
+		_, err := NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{MaxAttempts: &maxAttempts}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		var wg sync.WaitGroup
+		var started int32
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := serv.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+				if err == nil {
+					atomic.AddInt32(&started, 1)
+					return
+				}
+
+				assert.Equal(t, ErrAttemptInProgress, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), started)
+	})
+}
+
+// TestConcurrentSubmitAnswer will test that concurrent submissions for an attempt are neither lost nor counted twice.
+func TestConcurrentSubmitAnswer(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 10)
+
+		userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		userQuiz := models.UserQuizAttempts{
+			UserID: userID,
+			QuizID: quiz.ID,
+		}
+
+		err := serv.StartQuiz(&userQuiz)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		var wg sync.WaitGroup
+		var submitted int32
+
+		for _, question := range quiz.Questions {
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func(question models.Question) {
+					defer wg.Done()
+
+					response := models.UserResponse{
+						UserID:            userID,
+						QuizID:            quiz.ID,
+						UserQuizAttemptID: userQuiz.ID,
+						QuestionID:        question.ID,
+						SelectedOptionID:  question.Options[0].ID,
+					}
+
+					_, err := serv.SubmitAnswer(&response)
+					if err == nil {
+						atomic.AddInt32(&submitted, 1)
+						return
+					}
+
+					assert.Contains(t, []string{
+						"question already answered",
+						"cannot answer questions after quiz has ended",
+					}, err.Error())
+				}(question)
+			}
+		}
+
+		wg.Wait()
+
+		attempt, err := database.GetAttemptByID(userQuiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(len(quiz.Questions)), submitted)
+		assert.Equal(t, len(quiz.Questions), len(attempt.UserResponses))
+		assert.Equal(t, float64(len(quiz.Questions)), attempt.TotalScore)
+		assert.NotNil(t, attempt.EndedAt)
+	})
+}
+
+// createTestQuiz will create quiz with given number of questions, first option of each question is correct.
+// Quiz is created by the dummy user "userone".
+func createTestQuiz(t testing.TB, database db.Repository, totalQuestions int) *models.Quiz {
+	trueValue := true
+	falseValue := false
+	createdBy, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+	quiz := models.Quiz{
+		Title:     "Quiz " + uuid.NewString()[:8],
+		CreatedBy: createdBy,
 	}
 
-	response := models.UserResponse{
-		QuestionID:       uuid.New(),
-		SelectedOptionID: uuid.New(),
-		QuizID:           quizID,
-		UserID:           userID,
+	for i := 0; i < totalQuestions; i++ {
+		quiz.Questions = append(quiz.Questions, models.Question{
+			Text: "Question",
+			Options: []models.Option{
+				{Answer: "Answer 1", IsCorrect: &trueValue},
+				{Answer: "Answer 2", IsCorrect: &falseValue},
+				{Answer: "Answer 3", IsCorrect: &falseValue},
+				{Answer: "Answer 4", IsCorrect: &falseValue},
+			},
+		})
 	}
 
-	database.UserQuizAttempts = append(database.UserQuizAttempts, userQuiz)
+	err := NewQuizService(database).Create(&quiz)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	_, err := serv.SubmitAnswer(&response)
+	return &quiz
+}
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "cannot answer questions after quiz has ended", err.Error())
+// TestSubmitAnswerForUpdatedQuiz will test that answers are graded against the quiz version the attempt started on.
+func TestSubmitAnswerForUpdatedQuiz(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		userQuiz := models.UserQuizAttempts{
+			UserID: quiz.CreatedBy,
+			QuizID: quiz.ID,
+		}
+
+		err := serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), userQuiz.QuizVersion)
+
+		removedQuestion := quiz.Questions[0]
+
+		updatedQuiz := *quiz
+		updatedQuiz.Questions = []models.Question{quiz.Questions[1]}
+		err = quizService.Update(&updatedQuiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(2), updatedQuiz.Version)
+
+		response := models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        removedQuestion.ID,
+			SelectedOptionID:  removedQuestion.Options[0].ID,
+		}
+
+		_, err = serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.True(t, *response.IsCorrect)
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), result.Quiz.Version)
+		assert.Equal(t, 2, len(result.Quiz.Questions))
+		assert.Equal(t, 1.0, result.TotalScore)
+	})
 }
 
-// TestTimeExceededSubmission will test for submission after maximum time has passed
-func TestTimeExceededSubmission(t *testing.T) {
-	database := db.NewDatabase()
-	serv := NewUserQuizService(database)
-
-	quizID, _ := uuid.Parse("997f06f9-89d1-4f95-9300-09caee4d6b40")
-	userID, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
-	startedTime := time.Now().Add(-2 * time.Minute)
-
-	userQuiz := models.UserQuizAttempts{
-		UserID:    userID,
-		QuizID:    quizID,
-		StartedAt: &startedTime,
-		EndedAt:   nil,
+// TestSubmitAnswerForQuestionTypes will test that answers are graded by the type of question.
+func TestSubmitAnswerForQuestionTypes(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		numericAnswer := 42.0
+		createdBy, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		quiz := models.Quiz{
+			Title:     "Question types",
+			CreatedBy: createdBy,
+			Questions: []models.Question{
+				{Text: "Capital of France", Type: models.QuestionTypeShortText, AcceptedAnswers: []string{"Paris"}},
+				{Text: "Answer to everything", Type: models.QuestionTypeNumeric, NumericAnswer: &numericAnswer, Tolerance: 0.5},
+			},
+		}
+
+		err := NewQuizService(database).Create(&quiz)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		userQuiz := models.UserQuizAttempts{UserID: createdBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		textResponse := models.UserResponse{
+			UserID:            createdBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			TextAnswer:        "paris",
+		}
+
+		answerResult, err := serv.SubmitAnswer(&textResponse)
+		assert.Nil(t, err)
+		assert.True(t, *textResponse.IsCorrect)
+		assert.Equal(t, []string{"Paris"}, answerResult.CorrectAnswer.AcceptedAnswers)
+
+		numericResponse := textResponse
+		numericResponse.QuestionID = quiz.Questions[1].ID
+		numericResponse.TextAnswer = ""
+
+		_, err = serv.SubmitAnswer(&numericResponse)
+		assert.NotNil(t, err)
+		assert.Equal(t, "numeric answer is required", err.Error())
+
+		wrongAnswer := 40.0
+		numericResponse.NumericAnswer = &wrongAnswer
+
+		answerResult, err = serv.SubmitAnswer(&numericResponse)
+		assert.Nil(t, err)
+		assert.False(t, *numericResponse.IsCorrect)
+		assert.Equal(t, numericAnswer, *answerResult.CorrectAnswer.NumericAnswer)
+
+		result, err := serv.GetUserQuizResults(createdBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, result.TotalScore)
+		assert.NotNil(t, result.EndedAt)
+		assert.Nil(t, result.Quiz.Questions[0].AcceptedAnswers)
+		assert.Nil(t, result.Quiz.Questions[1].NumericAnswer)
+	})
+}
+
+// TestSubmitAnswerScoring will test weighted points, negative marking and partial credit.
+func TestSubmitAnswerScoring(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		trueValue := true
+		falseValue := false
+		createdBy, _ := uuid.Parse("bfc8ec19-124b-40a1-8936-12dace6fd162")
+
+		quiz := models.Quiz{
+			Title:           "Weighted quiz",
+			CreatedBy:       createdBy,
+			NegativeMarking: 0.5,
+			Questions: []models.Question{
+				{Text: "Capital of France", Type: models.QuestionTypeShortText, Points: 2, AcceptedAnswers: []string{"Paris"}},
+				{Text: "Prime numbers", Type: models.QuestionTypeMultipleChoice, Points: 4, Options: []models.Option{
+					{Answer: "2", IsCorrect: &trueValue},
+					{Answer: "3", IsCorrect: &trueValue},
+					{Answer: "4", IsCorrect: &falseValue},
+				}},
+			},
+		}
+
+		err := NewQuizService(database).Create(&quiz)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		userQuiz := models.UserQuizAttempts{UserID: createdBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+		assert.Equal(t, 6.0, userQuiz.MaxScore)
+
+		response := models.UserResponse{
+			UserID:            createdBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			TextAnswer:        "London",
+		}
+
+		// wrong answer loses half of the points of question.
+		answerResult, err := serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, -1.0, *answerResult.Score)
+		assert.Equal(t, -1.0, *answerResult.TotalScore)
+
+		response.QuestionID = quiz.Questions[1].ID
+		response.TextAnswer = ""
+		response.SelectedOptionIDs = []uuid.UUID{quiz.Questions[1].Options[0].ID}
+
+		// one of the two correct options gets half of the points.
+		answerResult, err = serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.False(t, *answerResult.IsCorrect)
+		assert.Equal(t, 2.0, *answerResult.Score)
+		assert.Equal(t, 1.0, *answerResult.TotalScore)
+		assert.Equal(t, 6.0, answerResult.MaxScore)
+		assert.Equal(t, 16.67, *answerResult.Percentage)
+
+		result, err := serv.GetUserQuizResults(createdBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, result.TotalScore)
+		assert.Equal(t, 16.67, result.Percentage)
+		assert.Equal(t, 4.0, result.Quiz.Questions[1].Points)
+	})
+}
+
+// TestAttemptOutcome will test that finished attempt is judged against passing percentage of quiz.
+func TestAttemptOutcome(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+		passingPercentage := 60.0
+
+		_, err := NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{PassingPercentage: &passingPercentage}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		for i, question := range quiz.Questions {
+			// first question is answered correctly and second one wrong.
+			response := models.UserResponse{
+				UserID:            quiz.CreatedBy,
+				QuizID:            quiz.ID,
+				UserQuizAttemptID: userQuiz.ID,
+				QuestionID:        question.ID,
+				SelectedOptionID:  question.Options[i].ID,
+			}
+
+			_, err = serv.SubmitAnswer(&response)
+			assert.Nil(t, err)
+		}
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 50.0, result.Percentage)
+		assert.Equal(t, passingPercentage, result.PassingPercentage)
+		assert.Equal(t, models.OutcomeFailed, result.Outcome)
+	})
+}
+
+func TestFinishAttempt(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err := serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		response := models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			SelectedOptionID:  quiz.Questions[0].Options[0].ID,
+		}
+
+		_, err = serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+
+		_, err = serv.FinishAttempt(uuid.New(), quiz.ID, userQuiz.ID)
+		assert.NotNil(t, err)
+
+		result, err := serv.FinishAttempt(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Nil(t, err)
+		assert.NotNil(t, result.EndedAt)
+		assert.Equal(t, 50.0, result.Percentage)
+		assert.Equal(t, models.OutcomePassed, result.Outcome)
+		assert.Len(t, result.UserResponses, 2)
+		assert.False(t, result.UserResponses[0].IsSkipped)
+		assert.True(t, result.UserResponses[1].IsSkipped)
+		assert.Equal(t, quiz.Questions[1].ID, result.UserResponses[1].QuestionID)
+		assert.Equal(t, 0.0, *result.UserResponses[1].Score)
+
+		_, err = serv.FinishAttempt(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Equal(t, "quiz has already ended", err.Error())
+
+		response.QuestionID = quiz.Questions[1].ID
+		response.SelectedOptionID = quiz.Questions[1].Options[0].ID
+		_, err = serv.SubmitAnswer(&response)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestRetakePolicy will test that quiz can be attempted again within its max attempts after cooldown,
+// and that final result is decided by its scoring policy.
+func TestRetakePolicy(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		clock := newFakeClock()
+		serv := NewUserQuizService(database, WithClock(clock))
+		quiz := createTestQuiz(t, database, 2)
+
+		maxAttempts, cooldown, passingPercentage := uint32(3), uint64(10), 60.0
+		_, err := NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{
+			MaxAttempts:       &maxAttempts,
+			AttemptCooldown:   &cooldown,
+			PassingPercentage: &passingPercentage,
+		}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		// attemptQuiz will start an attempt and answer given number of questions correctly and the rest wrong.
+		attemptQuiz := func(totalCorrect int) models.UserQuizAttempts {
+			userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+			err := serv.StartQuiz(&userQuiz)
+			assert.Nil(t, err)
+
+			for i, question := range quiz.Questions {
+				optionIndex := 1
+				if i < totalCorrect {
+					optionIndex = 0
+				}
+
+				_, err = serv.SubmitAnswer(&models.UserResponse{
+					UserID:            quiz.CreatedBy,
+					QuizID:            quiz.ID,
+					UserQuizAttemptID: userQuiz.ID,
+					QuestionID:        question.ID,
+					SelectedOptionID:  question.Options[optionIndex].ID,
+				})
+				assert.Nil(t, err)
+			}
+
+			return userQuiz
+		}
+
+		first := attemptQuiz(2)
+		assert.Equal(t, uint32(1), first.AttemptNumber)
+
+		err = serv.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "next attempt of this quiz can be started after")
+
+		attempts, err := serv.ListAttempts(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(2), attempts.AttemptsRemaining)
+		assert.NotNil(t, attempts.NextAttemptAt)
+
+		clock.Advance(11 * time.Minute)
+		second := attemptQuiz(1)
+		assert.Equal(t, uint32(2), second.AttemptNumber)
+
+		attempts, err = serv.ListAttempts(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Len(t, attempts.Attempts, 2)
+		assert.Equal(t, models.ScoringPolicyBest, attempts.ScoringPolicy)
+		assert.Equal(t, first.ID, *attempts.CountedAttemptID)
+		assert.Equal(t, 100.0, attempts.Percentage)
+		assert.Equal(t, models.OutcomePassed, attempts.Outcome)
+
+		for _, policy := range []string{models.ScoringPolicyLatest, models.ScoringPolicyAverage} {
+			_, err = NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{ScoringPolicy: &policy}, quiz.CreatedBy)
+			assert.Nil(t, err)
+		}
+
+		attempts, err = serv.ListAttempts(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, attempts.CountedAttemptID)
+		assert.Equal(t, 75.0, attempts.Percentage)
+		assert.Equal(t, models.OutcomePassed, attempts.Outcome)
+
+		// finished attempts keep being judged against the passing percentage they ended with.
+		passingPercentage = 90
+		_, err = NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{PassingPercentage: &passingPercentage}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		attempts, err = serv.ListAttempts(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 75.0, attempts.Percentage)
+		assert.Equal(t, models.OutcomePassed, attempts.Outcome)
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, second.ID, result.ID)
+
+		result, err = serv.GetAttemptResults(quiz.CreatedBy, quiz.ID, first.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 100.0, result.Percentage)
+
+		_, err = serv.GetAttemptResults(uuid.New(), quiz.ID, first.ID)
+		assert.NotNil(t, err)
+
+		clock.Advance(11 * time.Minute)
+		third := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&third)
+		assert.Nil(t, err)
+
+		err = serv.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+		assert.Equal(t, "user has already attempted this quiz", err.Error())
+	})
+}
+
+// TestGetCurrentAttempt will test that attempt in progress can be fetched to continue it.
+func TestGetCurrentAttempt(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		clock := newFakeClock()
+		serv := NewUserQuizService(database, WithClock(clock))
+		quiz := createTestQuiz(t, database, 2)
+
+		_, err := serv.GetCurrentAttempt(quiz.CreatedBy, quiz.ID)
+		assert.Equal(t, "no attempt in progress for this quiz", err.Error())
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		_, err = serv.SubmitAnswer(&models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			SelectedOptionID:  quiz.Questions[0].Options[0].ID,
+		})
+		assert.Nil(t, err)
+
+		clock.Advance(30 * time.Second)
+
+		currentAttempt, err := serv.GetCurrentAttempt(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, userQuiz.ID, currentAttempt.ID)
+		assert.Equal(t, int64(quiz.MaxTime*60-30), currentAttempt.RemainingSeconds)
+		assert.Equal(t, 2, currentAttempt.TotalQuestions)
+		assert.Equal(t, []uuid.UUID{quiz.Questions[0].ID}, currentAttempt.AnsweredQuestionIDs)
+		assert.Equal(t, quiz.Questions[1].ID, currentAttempt.NextQuestion.ID)
+		assert.Nil(t, currentAttempt.NextQuestion.Options[0].IsCorrect)
+
+		clock.Advance(time.Duration(quiz.MaxTime) * time.Minute)
+
+		_, err = serv.GetCurrentAttempt(quiz.CreatedBy, quiz.ID)
+		assert.Equal(t, "no attempt in progress for this quiz", err.Error())
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.OutcomeTimedOut, result.Outcome)
+	})
+}
+
+// TestGetAttemptQuestions will test that questions and options are shuffled per attempt and keep their order on reload.
+func TestGetAttemptQuestions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 8)
+		shuffle := true
+
+		updatedQuiz, err := NewQuizService(database).Patch(quiz.ID, &models.QuizPatch{
+			ShuffleQuestions: &shuffle,
+			ShuffleOptions:   &shuffle,
+		}, quiz.CreatedBy)
+		assert.Nil(t, err)
+		assert.Equal(t, quiz.Version+1, updatedQuiz.Version)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		attemptQuiz, err := serv.GetAttemptQuestions(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Nil(t, err)
+		assert.Len(t, attemptQuiz.Questions, len(quiz.Questions))
+
+		reloadedQuiz, err := serv.GetAttemptQuestions(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, attemptQuiz.Questions, reloadedQuiz.Questions)
+
+		questionIDs := map[uuid.UUID]bool{}
+		for _, question := range attemptQuiz.Questions {
+			questionIDs[question.ID] = true
+			assert.Len(t, question.Options, 4)
+
+			for _, option := range question.Options {
+				assert.Nil(t, option.IsCorrect)
+			}
+		}
+
+		for _, question := range quiz.Questions {
+			assert.True(t, questionIDs[question.ID])
+		}
+
+		currentAttempt, err := serv.GetCurrentAttempt(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, attemptQuiz.Questions[0], *currentAttempt.NextQuestion)
+
+		_, err = serv.GetAttemptQuestions(quiz.CreatedBy, quiz.ID, uuid.New())
+		assert.Equal(t, "attempt not found", err.Error())
+	})
+}
+
+// TestAttemptQuestionsOrder will test that order of questions and options only changes when quiz enables shuffling.
+func TestAttemptQuestionsOrder(t *testing.T) {
+	quiz := &models.Quiz{ID: uuid.MustParse("997f06f9-89d1-4f95-9300-09caee4d6b40")}
+	for i := 0; i < 10; i++ {
+		quiz.Questions = append(quiz.Questions, models.Question{
+			ID:      uuid.New(),
+			Options: []models.Option{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}},
+		})
 	}
 
-	response := models.UserResponse{
-		QuestionID:       uuid.New(),
-		SelectedOptionID: uuid.New(),
-		QuizID:           quizID,
-		UserID:           userID,
+	attempt := &models.UserQuizAttempts{ID: uuid.MustParse("a8448fc1-bf25-4903-9b9e-50579be43c35")}
+
+	questions := attemptQuestions(attempt, quiz)
+	for i := range quiz.Questions {
+		assert.Equal(t, quiz.Questions[i].ID, questions[i].ID)
+		assert.Equal(t, quiz.Questions[i].Options[0].ID, questions[i].Options[0].ID)
 	}
 
-	database.UserQuizAttempts = append(database.UserQuizAttempts, userQuiz)
+	quiz.ShuffleQuestions = true
+	shuffled := attemptQuestions(attempt, quiz)
+	assert.Equal(t, shuffled, attemptQuestions(attempt, quiz))
+	assert.NotEqual(t, questions, shuffled)
+
+	// options keep their order unless they are shuffled as well.
+	for _, question := range shuffled {
+		for i := range quiz.Questions {
+			if quiz.Questions[i].ID == question.ID {
+				assert.Equal(t, quiz.Questions[i].Options[0].ID, question.Options[0].ID)
+			}
+		}
+	}
+}
 
-	_, err := serv.SubmitAnswer(&response)
+// TestQuestionPools will test that every attempt is asked questions drawn for it from pools of quiz.
+func TestQuestionPools(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 7)
+
+		// first two questions are always asked, and two out of remaining five are drawn.
+		quiz.Pools = []models.QuestionPool{{Name: "capitals", DrawCount: 2}}
+		for i := 2; i < len(quiz.Questions); i++ {
+			quiz.Questions[i].Pool = "capitals"
+		}
+
+		err := NewQuizService(database).Update(quiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+		assert.Len(t, userQuiz.QuestionIDs, 4)
+		assert.Equal(t, []uuid.UUID{quiz.Questions[0].ID, quiz.Questions[1].ID}, userQuiz.QuestionIDs[:2])
+		assert.Equal(t, 4.0, userQuiz.MaxScore)
+
+		attemptQuiz, err := serv.GetAttemptQuestions(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Nil(t, err)
+		assert.Len(t, attemptQuiz.Questions, 4)
+
+		drawn := map[uuid.UUID]bool{}
+		for _, questionID := range userQuiz.QuestionIDs {
+			drawn[questionID] = true
+		}
+
+		for _, question := range quiz.Questions {
+			if drawn[question.ID] {
+				continue
+			}
+
+			_, err = serv.SubmitAnswer(&models.UserResponse{
+				UserID:            quiz.CreatedBy,
+				QuizID:            quiz.ID,
+				UserQuizAttemptID: userQuiz.ID,
+				QuestionID:        question.ID,
+				SelectedOptionID:  question.Options[0].ID,
+			})
+			assert.Equal(t, "question not found for specified quiz", err.Error())
+		}
+
+		for _, question := range attemptQuiz.Questions {
+			_, err = serv.SubmitAnswer(&models.UserResponse{
+				UserID:            quiz.CreatedBy,
+				QuizID:            quiz.ID,
+				UserQuizAttemptID: userQuiz.ID,
+				QuestionID:        question.ID,
+				SelectedOptionID:  question.Options[0].ID,
+			})
+			assert.Nil(t, err)
+		}
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.NotNil(t, result.EndedAt)
+		assert.Equal(t, 100.0, result.Percentage)
+		assert.Len(t, result.Quiz.Questions, 4)
+	})
+}
+
+// TestFeedbackMode will test that correctness of answers is only revealed when feedback mode of quiz allows it.
+func TestFeedbackMode(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		clock := newFakeClock()
+		serv := NewUserQuizService(database, WithClock(clock))
+		quizService := NewQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		feedbackMode := models.FeedbackAfterAttempt
+		_, err := quizService.Patch(quiz.ID, &models.QuizPatch{FeedbackMode: &feedbackMode}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		response := models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			SelectedOptionID:  quiz.Questions[0].Options[0].ID,
+		}
+
+		answerResult, err := serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, models.FeedbackAfterAttempt, answerResult.FeedbackMode)
+		assert.Nil(t, answerResult.IsCorrect)
+		assert.Nil(t, answerResult.Score)
+		assert.Nil(t, answerResult.CorrectAnswer)
+		assert.Nil(t, answerResult.CorrectOption)
+		assert.Nil(t, answerResult.TotalScore)
+
+		currentAttempt, err := serv.GetCurrentAttempt(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, currentAttempt.UserResponses[0].IsCorrect)
+		assert.Equal(t, 0.0, currentAttempt.TotalScore)
+
+		response.QuestionID = quiz.Questions[1].ID
+		response.SelectedOptionID = quiz.Questions[1].Options[1].ID
+		answerResult, err = serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.False(t, *answerResult.IsCorrect)
+		assert.Equal(t, 50.0, *answerResult.Percentage)
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.True(t, *result.UserResponses[0].IsCorrect)
+
+		feedbackMode = models.FeedbackAfterClose
+		_, err = quizService.Patch(quiz.ID, &models.QuizPatch{FeedbackMode: &feedbackMode}, quiz.CreatedBy)
+		assert.Equal(t, "closes at must be specified when feedback is revealed after quiz closes", err.Error())
+
+		closesAt := clock.Now().Add(time.Hour)
+		_, err = quizService.Patch(quiz.ID, &models.QuizPatch{FeedbackMode: &feedbackMode, ClosesAt: &closesAt}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		result, err = serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, result.UserResponses[0].IsCorrect)
+		assert.Nil(t, result.UserResponses[0].Score)
+		assert.Equal(t, 50.0, result.Percentage)
+
+		clock.Advance(time.Hour)
+		result, err = serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.True(t, *result.UserResponses[0].IsCorrect)
+
+		err = serv.StartQuiz(&models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID})
+		assert.Equal(t, "quiz has closed and cannot be attempted", err.Error())
+
+		feedbackMode = models.FeedbackNever
+		_, err = quizService.Patch(quiz.ID, &models.QuizPatch{FeedbackMode: &feedbackMode}, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		attempts, err := serv.ListAttempts(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, attempts.Attempts[0].UserResponses[0].IsCorrect)
+	})
+}
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "maximum time exceeded for this quiz", err.Error())
+// TestExplanations will test that explanations of questions and feedback of options are only shown once feedback is revealed.
+func TestExplanations(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, database db.Repository) {
+		serv := NewUserQuizService(database)
+		quiz := createTestQuiz(t, database, 2)
+
+		for i := range quiz.Questions {
+			quiz.Questions[i].Explanation = "Answer 1 is always correct."
+			quiz.Questions[i].Options[1].Feedback = "Answer 2 is never correct."
+		}
+
+		quiz.FeedbackMode = models.FeedbackAfterAttempt
+		err := NewQuizService(database).Update(quiz, quiz.CreatedBy)
+		assert.Nil(t, err)
+
+		userQuiz := models.UserQuizAttempts{UserID: quiz.CreatedBy, QuizID: quiz.ID}
+		err = serv.StartQuiz(&userQuiz)
+		assert.Nil(t, err)
+
+		questions, err := serv.GetAttemptQuestions(quiz.CreatedBy, quiz.ID, userQuiz.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, questions.Questions[0].Explanation)
+		assert.Empty(t, questions.Questions[0].Options[1].Feedback)
+
+		response := models.UserResponse{
+			UserID:            quiz.CreatedBy,
+			QuizID:            quiz.ID,
+			UserQuizAttemptID: userQuiz.ID,
+			QuestionID:        quiz.Questions[0].ID,
+			SelectedOptionID:  quiz.Questions[0].Options[1].ID,
+		}
+
+		answerResult, err := serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.Empty(t, answerResult.Explanation)
+		assert.Empty(t, answerResult.SelectedOptions)
+
+		response.QuestionID = quiz.Questions[1].ID
+		response.SelectedOptionID = quiz.Questions[1].Options[1].ID
+		answerResult, err = serv.SubmitAnswer(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, "Answer 1 is always correct.", answerResult.Explanation)
+		assert.Len(t, answerResult.SelectedOptions, 1)
+		assert.Equal(t, "Answer 2 is never correct.", answerResult.SelectedOptions[0].Feedback)
+		assert.False(t, *answerResult.SelectedOptions[0].IsCorrect)
+
+		result, err := serv.GetUserQuizResults(quiz.CreatedBy, quiz.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Answer 1 is always correct.", result.Quiz.Questions[0].Explanation)
+		assert.Equal(t, "Answer 2 is never correct.", result.Quiz.Questions[0].Options[1].Feedback)
+		assert.Nil(t, result.Quiz.Questions[0].Options[1].IsCorrect)
+	})
 }
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrFileNotFound is returned by a file storage when no file is stored under the requested key.
+var ErrFileNotFound = errors.New("file not found")
+
+// FileStorage will consist of methods to store content of uploaded files.
+// Keys are generated by the services and must not contain path separators.
+type FileStorage interface {
+	Save(key string, content io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// LocalStorage will store files in a directory of the local filesystem.
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage will create storage keeping files in dir, dir is created if it does not exist.
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save will write content to the file stored under key, replacing it if it exists.
+// Content is written to a temporary file and renamed so that a partial file is never served.
+func (s *LocalStorage) Save(key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, content)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Open will open the file stored under key for reading, caller must close it.
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Delete will remove the file stored under key.
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrFileNotFound
+	}
+
+	return err
+}
+
+// path will return path of the file stored under key, keys which could refer to a file outside dir are rejected.
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", errors.New("invalid file key")
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
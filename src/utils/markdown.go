@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown converts GitHub flavoured Markdown to HTML. Raw HTML in the source is not rendered.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// htmlPolicy removes every element and attribute which could run scripts from rendered HTML.
+// Language of code blocks is kept so that clients can highlight them.
+var htmlPolicy = newHTMLPolicy()
+
+// ValidateMarkdown validates that input is valid UTF-8 without control characters other than tabs and new lines.
+func ValidateMarkdown(input string) bool {
+	if !utf8.ValidString(input) {
+		return false
+	}
+
+	for _, r := range input {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newHTMLPolicy creates policy allowing HTML which is commonly used in user generated content.
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+	return policy
+}
+
+// RenderMarkdown renders Markdown source to sanitized HTML, which is safe to show in a browser.
+func RenderMarkdown(source string) string {
+	if source == "" {
+		return ""
+	}
+
+	var rendered bytes.Buffer
+
+	err := markdown.Convert([]byte(source), &rendered)
+	if err != nil {
+		// source is still shown, escaped as plain text.
+		return html.EscapeString(source)
+	}
+
+	return string(bytes.TrimSpace(htmlPolicy.SanitizeBytes(rendered.Bytes())))
+}